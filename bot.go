@@ -1,23 +1,35 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"net/url"
+	"strings"
+	"sync"
 
 	"github.com/programmingparody/amazing-bot/chatapp"
-	"github.com/programmingparody/amazing-bot/scrapers/amazonscraper"
+	"github.com/programmingparody/amazing-bot/scrapers"
 )
 
 /*AmazingBot Chat Bot for Discord / Slack (Maybe Zoom soon)
 Usage:
 	Hook into chatapp sessions and set up listeners/handlers for Reports and Messages
-	When a message comes through, check for Amazon links
-	Valid Amazon links are ran through a Fetcher
+	When a message comes through, check for product links known to Scrapers (Amazon, ...)
+	Valid product links are ran through a Fetcher
 	Successful responses from Fetcher are sent back to the chat using RespondWithProduct
+	Commands ("!amazing search <keywords>") are handled by handleCommand
+	Products can be tracked (see tracker) with the track command, or by reacting to a product the bot posted
 */
 type AmazingBot struct {
 	Fetcher            ProductFetcher
+	Searcher           ProductSearcher
+	SearchResultsCount int //Number of results replied to a search command
+	Scrapers           *scrapers.Registry
 	ProductSentHandler func(e *SentProductEvent)
 	ReportHandler      chatapp.OnProductProblemReportCallback
+	Tracker            *tracker    //Optional, enables the track commands
+	SentProducts       ProductRepo //Products the bot posted by message ID, to track them through reactions
+	DisableComparison  bool        //Respond to each link separately, instead of comparing the products of messages with several links
 }
 
 //SentProductEvent will be fired to a callback when a product is sent
@@ -31,6 +43,11 @@ type SentProductEvent struct {
 func (ab *AmazingBot) Hook(s chatapp.Session) {
 	s.OnMessage(ab.createOnMessageHandler())
 	s.OnProductProblemReport(ab.ReportHandler)
+	s.OnCommand(ab.handleCommand)
+	if notifier, ok := s.(chatapp.Notifier); ok && ab.Tracker != nil {
+		ab.Tracker.addNotifier(notifier)
+		s.OnTrackRequest(ab.handleTrackRequest)
+	}
 }
 
 func (ab *AmazingBot) createOnMessageHandler() func(c chatapp.Session, m *chatapp.Message) {
@@ -45,36 +62,182 @@ func (ab *AmazingBot) createOnReport() func(c chatapp.Session, m *chatapp.Messag
 }
 
 func (ab *AmazingBot) handleMessage(c chatapp.Session, m *chatapp.Message) {
-	// Ignore all messages created by the bot
-	if m.MessageIsFromThisBot {
+	// Ignore all messages created by the bot, and commands (see handleCommand)
+	if m.MessageIsFromThisBot || chatapp.IsCommand(m.Content) {
 		return
 	}
 
-	amazonLinks := amazonscraper.ExtractManyProductLinkFromString(m.Content)
+	productLinks := ab.uniqueProductLinks(ab.Scrapers.ExtractLinks(m.Content))
 
-	if len(amazonLinks) == 0 {
+	if len(productLinks) == 0 {
+		return
+	}
+	if !ab.DisableComparison && len(productLinks) >= 2 && len(productLinks) <= chatapp.MaxComparedProducts {
+		go ab.compare(m, productLinks)
 		return
 	}
-	for _, link := range amazonLinks {
+	for _, link := range productLinks {
 		URL, error := url.Parse(link)
 		if error != nil {
 			return
 		}
 
 		go func(URL *url.URL) {
-			p, _ := ab.Fetcher.Fetch(URL)
-			_, wholeMessageAsURLError := url.Parse(m.Content)
-			if wholeMessageAsURLError == nil {
-				go m.Actions.Remove()
-			}
-			id, _ := m.Actions.RespondWithProduct(p)
-			if ab.ProductSentHandler != nil {
-				ab.ProductSentHandler(&SentProductEvent{
-					ResponseToMessage: m,
-					NewMessageID:      id,
-					Product:           p,
-				})
+			p, error := ab.Fetcher.Fetch(URL)
+			if error != nil {
+				ab.handleFetchError(m, error)
+				return
 			}
+			ab.respondWithProduct(m, p)
 		}(URL)
 	}
 }
+
+//respondWithProduct posts a product in reply to m, removing m when it was only the link
+func (ab *AmazingBot) respondWithProduct(m *chatapp.Message, p *chatapp.Product) {
+	_, wholeMessageAsURLError := url.Parse(m.Content)
+	if wholeMessageAsURLError == nil {
+		go m.Actions.Remove()
+	}
+	id, _ := m.Actions.RespondWithProduct(p)
+	if ab.ProductSentHandler != nil {
+		ab.ProductSentHandler(&SentProductEvent{
+			ResponseToMessage: m,
+			NewMessageID:      id,
+			Product:           p,
+		})
+	}
+}
+
+//compare fetches the products of every link at once, and posts them in a single comparison
+//Falls back to a regular product response when only one of them could be fetched
+func (ab *AmazingBot) compare(m *chatapp.Message, links []string) {
+	products := make([]*chatapp.Product, len(links))
+	fetchErrors := make([]error, len(links))
+	var wait sync.WaitGroup
+	for index, link := range links {
+		URL, error := url.Parse(link)
+		if error != nil {
+			fetchErrors[index] = error
+			continue
+		}
+		wait.Add(1)
+		go func(index int, URL *url.URL) {
+			defer wait.Done()
+			products[index], fetchErrors[index] = ab.Fetcher.Fetch(URL)
+		}(index, URL)
+	}
+	wait.Wait()
+
+	//Short links are only known to be the same product once fetched
+	fetched := []*chatapp.Product{}
+	seen := map[string]bool{}
+	var firstError error
+	for index, p := range products {
+		if fetchErrors[index] != nil {
+			if firstError == nil {
+				firstError = fetchErrors[index]
+			}
+			continue
+		}
+		if key := ab.productKey(p.URL); !seen[key] {
+			seen[key] = true
+			fetched = append(fetched, p)
+		}
+	}
+
+	switch len(fetched) {
+	case 0:
+		ab.handleFetchError(m, firstError)
+	case 1:
+		ab.respondWithProduct(m, fetched[0])
+	default:
+		m.Actions.RespondWithComparison(fetched)
+	}
+}
+
+//uniqueProductLinks drops links to a product already linked earlier in the list ("/dp/B000000001" and "/gp/product/B000000001")
+func (ab *AmazingBot) uniqueProductLinks(links []string) []string {
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, link := range links {
+		URL, error := url.Parse(link)
+		if error != nil {
+			continue
+		}
+		if key := ab.productKey(URL); !seen[key] {
+			seen[key] = true
+			unique = append(unique, link)
+		}
+	}
+	return unique
+}
+
+//productKey identifies the product of a URL, falling back to the URL itself when its scraper can't (short links)
+func (ab *AmazingBot) productKey(URL *url.URL) string {
+	if URL == nil {
+		return ""
+	}
+	if scraper, error := ab.Scrapers.ScraperFor(URL); error == nil {
+		if key, error := scraper.ProductKey(URL); error == nil {
+			return key.String()
+		}
+	}
+	return URL.String()
+}
+
+//handleFetchError posts a friendly notice when the retailer blocked or lost the product
+//Anything else (not a product page, network errors, ...) is declined silently
+func (ab *AmazingBot) handleFetchError(m *chatapp.Message, e error) {
+	switch {
+	case errors.Is(e, scrapers.ErrBlocked):
+		m.Actions.RespondWithText("The store asked me to prove I'm not a robot, so I couldn't look up that product. Try again in a bit!")
+	case errors.Is(e, scrapers.ErrProductNotFound):
+		m.Actions.RespondWithText("That product page doesn't exist (anymore?)")
+	}
+}
+
+func (ab *AmazingBot) handleCommand(s chatapp.Session, c *chatapp.Command) {
+	switch c.Name {
+	case "search":
+		go ab.search(c)
+	case "track":
+		go ab.track(s, c)
+	case "untrack":
+		ab.untrack(s, c)
+	case "watchlist":
+		ab.watchlist(s, c)
+	default:
+		usage := []string{
+			"Usage:",
+			fmt.Sprintf("`%s search <keywords>` Find products", chatapp.CommandPrefix),
+		}
+		if _, ok := s.(chatapp.Notifier); ok && ab.Tracker != nil {
+			usage = append(usage,
+				fmt.Sprintf("`%s track <link> [target price] [dm]` Get pinged (or a direct message) when the price drops or it's back in stock", chatapp.CommandPrefix),
+				fmt.Sprintf("`%s untrack <link or watchlist number>` Stop tracking a product", chatapp.CommandPrefix),
+				fmt.Sprintf("`%s watchlist` List the products you track", chatapp.CommandPrefix),
+			)
+		}
+		c.Message.Actions.RespondWithText(strings.Join(usage, "\n"))
+	}
+}
+
+func (ab *AmazingBot) search(c *chatapp.Command) {
+	keywords := strings.Join(c.Args, " ")
+	if len(keywords) == 0 || ab.Searcher == nil {
+		c.Message.Actions.RespondWithText(fmt.Sprintf("Usage: `%s search <keywords>`", chatapp.CommandPrefix))
+		return
+	}
+
+	products, error := ab.Searcher.Search(keywords, ab.SearchResultsCount)
+	if error != nil {
+		ab.handleFetchError(c.Message, error)
+		return
+	}
+	if len(products) == 0 {
+		c.Message.Actions.RespondWithText(fmt.Sprintf("No results for \"%s\"", keywords))
+		return
+	}
+	c.Message.Actions.RespondWithProductList(fmt.Sprintf("Results for \"%s\"", keywords), products)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/programmingparody/amazing-bot/chatapp"
+	"github.com/programmingparody/amazing-bot/scrapers"
+	"github.com/programmingparody/amazing-bot/scrapers/amazonscraper"
+)
+
+type urlFetcher struct{}
+
+func (f *urlFetcher) Fetch(url *url.URL) (*chatapp.Product, error) {
+	return &chatapp.Product{URL: url, Title: url.Path}, nil
+}
+
+type testActions struct {
+	products    chan *chatapp.Product
+	comparisons chan []*chatapp.Product
+}
+
+func (a *testActions) Remove() error { return nil }
+func (a *testActions) RespondWithProduct(p *chatapp.Product) (string, error) {
+	a.products <- p
+	return "", nil
+}
+func (a *testActions) RespondWithText(text string) (string, error) { return "", nil }
+func (a *testActions) RespondWithProductList(title string, products []*chatapp.Product) (string, error) {
+	return "", nil
+}
+func (a *testActions) RespondWithComparison(products []*chatapp.Product) (string, error) {
+	a.comparisons <- products
+	return "", nil
+}
+
+func TestComparison(t *testing.T) {
+	content := "https://www.amazon.com/dp/B000000001\nor https://www.amazon.com/dp/B000000002"
+	for _, disableComparison := range []bool{false, true} {
+		ab := &AmazingBot{
+			Fetcher:           &urlFetcher{},
+			Scrapers:          scrapers.NewRegistry(amazonscraper.Scraper{}),
+			DisableComparison: disableComparison,
+		}
+		actions := &testActions{products: make(chan *chatapp.Product, 2), comparisons: make(chan []*chatapp.Product, 1)}
+		ab.handleMessage(nil, &chatapp.Message{Content: content, Actions: actions})
+		if disableComparison {
+			<-actions.products
+			<-actions.products
+			if len(actions.comparisons) != 0 {
+				t.Errorf("Comparison disabled, expected each product separately")
+			}
+			continue
+		}
+		comparison := <-actions.comparisons
+		if len(comparison) != 2 || len(actions.products) != 0 {
+			t.Fatalf("Expected a comparison of 2 products, got %d and %d products", len(comparison), len(actions.products))
+		}
+		if comparison[0].Title != "/dp/B000000001" || comparison[1].Title != "/dp/B000000002" {
+			t.Errorf("Comparison should keep the order of the links, got %s, %s", comparison[0].Title, comparison[1].Title)
+		}
+	}
+}
+
+func TestComparisonSameLine(t *testing.T) {
+	ab := &AmazingBot{
+		Fetcher:  &urlFetcher{},
+		Scrapers: scrapers.NewRegistry(amazonscraper.Scraper{}),
+	}
+
+	content := "Which one, https://www.amazon.com/dp/B000000001 or https://www.amazon.com/dp/B000000002?"
+	actions := &testActions{products: make(chan *chatapp.Product, 2), comparisons: make(chan []*chatapp.Product, 1)}
+	ab.handleMessage(nil, &chatapp.Message{Content: content, Actions: actions})
+	select {
+	case comparison := <-actions.comparisons:
+		if len(comparison) != 2 || comparison[0].Title != "/dp/B000000001" || comparison[1].Title != "/dp/B000000002" {
+			t.Errorf("Expected a comparison of both links of the line, got %+v", comparison)
+		}
+	case p := <-actions.products:
+		t.Errorf("Expected a comparison of both links of the line, got a single product %s", p.Title)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the comparison")
+	}
+}
+
+func TestComparisonSameProduct(t *testing.T) {
+	ab := &AmazingBot{
+		Fetcher:  &urlFetcher{},
+		Scrapers: scrapers.NewRegistry(amazonscraper.Scraper{}),
+	}
+
+	content := "https://www.amazon.com/dp/B000000001\nhttps://www.amazon.com/gp/product/B000000001?psc=1\nhttps://www.amazon.com/dp/B000000002"
+	actions := &testActions{products: make(chan *chatapp.Product, 3), comparisons: make(chan []*chatapp.Product, 1)}
+	ab.handleMessage(nil, &chatapp.Message{Content: content, Actions: actions})
+	if comparison := <-actions.comparisons; len(comparison) != 2 {
+		t.Errorf("Expected a comparison of 2 different products, got %d", len(comparison))
+	}
+
+	content = "https://www.amazon.com/dp/B000000001\nor https://www.amazon.com/gp/product/B000000001"
+	actions = &testActions{products: make(chan *chatapp.Product, 2), comparisons: make(chan []*chatapp.Product, 1)}
+	ab.handleMessage(nil, &chatapp.Message{Content: content, Actions: actions})
+	<-actions.products
+	if len(actions.comparisons) != 0 || len(actions.products) != 0 {
+		t.Errorf("Expected a single product for links to the same product")
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Package chart renders price history line charts as PNG images, without any external service
+
+Usage:
+	png, error := chart.Render(points, func(price float32) string { return chatapp.FormatPrice(price, "USD") })
+*/
+package chart
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"time"
+)
+
+//Size of rendered charts, and the margins around the plot area (for the axis labels)
+const (
+	Width        = 600
+	Height       = 300
+	marginLeft   = 90
+	marginRight  = 20
+	marginTop    = 20
+	marginBottom = 36
+	fontScale    = 2
+)
+
+var (
+	backgroundColor    = color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+	axisColor          = color.RGBA{0x99, 0x99, 0x99, 0xFF}
+	gridColor          = color.RGBA{0xEE, 0xEE, 0xEE, 0xFF}
+	textColor          = color.RGBA{0x33, 0x33, 0x33, 0xFF}
+	priceColor         = color.RGBA{0x14, 0x6E, 0xB4, 0xFF}
+	originalPriceColor = color.RGBA{0xAA, 0xAA, 0xAA, 0xFF}
+	dealColor          = color.RGBA{0xFF, 0x99, 0x00, 0xFF}
+	outOfStockColor    = color.RGBA{0xCC, 0x22, 0x22, 0xFF}
+)
+
+//ErrNotEnoughPoints is returned when less than 2 points have a price
+var ErrNotEnoughPoints = errors.New("[Chart] At least 2 prices are needed to draw a chart")
+
+//Point of a price history
+type Point struct {
+	Time          time.Time
+	Price         float32 //0 when the product had no price (out of stock)
+	OriginalPrice float32 //Strike-through price, 0 when there was none
+}
+
+//Render the points (oldest first) as a PNG line chart of the price
+//The strike-through price is drawn as a grey dashed line, deals (price below the strike-through price) as orange dots
+//and times without a price as red ticks on the time axis. formatPrice labels the price axis
+func Render(points []Point, formatPrice func(float32) string) ([]byte, error) {
+	priced := 0
+	var low, high float32
+	for _, p := range points {
+		for _, price := range []float32{p.Price, p.OriginalPrice} {
+			if price <= 0 {
+				continue
+			}
+			if priced == 0 || price < low {
+				low = price
+			}
+			if price > high {
+				high = price
+			}
+		}
+		if p.Price > 0 {
+			priced++
+		}
+	}
+	if priced < 2 {
+		return nil, ErrNotEnoughPoints
+	}
+	if high == low {
+		low, high = low*0.9, high*1.1
+	}
+	padding := (high - low) * 0.1
+	low, high = low-padding, high+padding
+	if low < 0 {
+		low = 0
+	}
+
+	start, end := points[0].Time, points[len(points)-1].Time
+	if !end.After(start) {
+		end = start.Add(time.Second)
+	}
+	left, right, top, bottom := marginLeft, Width-marginRight, marginTop, Height-marginBottom
+	x := func(t time.Time) int {
+		return left + int(float64(right-left)*float64(t.Sub(start))/float64(end.Sub(start)))
+	}
+	y := func(price float32) int {
+		return bottom - int(float32(bottom-top)*(price-low)/(high-low))
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	fillRect(img, 0, 0, Width, Height, backgroundColor)
+
+	//Grid and price labels
+	const gridLines = 4
+	for i := 0; i <= gridLines; i++ {
+		price := low + (high-low)*float32(i)/gridLines
+		lineY := y(price)
+		drawLine(img, left, lineY, right, lineY, 1, gridColor)
+		label := formatPrice(price)
+		drawText(img, label, left-8-textWidth(label, fontScale), lineY-glyphHeight*fontScale/2, fontScale, textColor)
+	}
+	drawLine(img, left, top, left, bottom, 1, axisColor)
+	drawLine(img, left, bottom, right, bottom, 1, axisColor)
+
+	//Date labels
+	const dateFormat = "01/02"
+	drawText(img, start.Format(dateFormat), left, bottom+10, fontScale, textColor)
+	endLabel := end.Format(dateFormat)
+	drawText(img, endLabel, right-textWidth(endLabel, fontScale), bottom+10, fontScale, textColor)
+
+	//Lines. Points without a price break the line
+	var previous, previousOriginal *Point
+	for i := range points {
+		p := &points[i]
+		if p.OriginalPrice > 0 && previousOriginal != nil {
+			drawDashedLine(img, x(previousOriginal.Time), y(previousOriginal.OriginalPrice), x(p.Time), y(p.OriginalPrice), originalPriceColor)
+		}
+		if p.Price > 0 && previous != nil {
+			drawLine(img, x(previous.Time), y(previous.Price), x(p.Time), y(p.Price), 2, priceColor)
+		}
+		previous, previousOriginal = nil, nil
+		if p.Price > 0 {
+			previous = p
+		}
+		if p.OriginalPrice > 0 {
+			previousOriginal = p
+		}
+	}
+
+	//Markers
+	for _, p := range points {
+		switch {
+		case p.Price <= 0:
+			fillRect(img, x(p.Time)-1, bottom-6, x(p.Time)+2, bottom, outOfStockColor)
+		case p.OriginalPrice > p.Price:
+			fillCircle(img, x(p.Time), y(p.Price), 4, dealColor)
+		}
+	}
+
+	var buffer bytes.Buffer
+	if error := png.Encode(&buffer, img); error != nil {
+		return nil, error
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,138 @@
+package chart
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	formatPrice := func(price float32) string { return fmt.Sprintf("$%.2f", price) }
+	start := time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC)
+	points := []Point{
+		{Time: start, Price: 59.99},
+		{Time: start.AddDate(0, 0, 5), Price: 49.99, OriginalPrice: 59.99},
+		{Time: start.AddDate(0, 0, 8)},
+		{Time: start.AddDate(0, 0, 12), Price: 54.99, OriginalPrice: 59.99},
+	}
+
+	data, error := Render(points, formatPrice)
+	if error != nil {
+		t.Fatal(error)
+	}
+	img, error := png.Decode(bytes.NewReader(data))
+	if error != nil {
+		t.Fatal(error)
+	}
+	if size := img.Bounds().Size(); size.X != Width || size.Y != Height {
+		t.Errorf("Expected: %dx%d Result: %v", Width, Height, size)
+	}
+
+	if _, error := Render(points[:1], formatPrice); error != ErrNotEnoughPoints {
+		t.Errorf("Expected: %v Result: %v", ErrNotEnoughPoints, error)
+	}
+}
+
+func TestRenderMarkers(t *testing.T) {
+	formatPrice := func(price float32) string { return fmt.Sprintf("$%.2f", price) }
+	start := time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC)
+	points := []Point{
+		{Time: start, Price: 59.99, OriginalPrice: 69.99},
+		{Time: start.AddDate(0, 0, 5), Price: 49.99, OriginalPrice: 69.99},
+		{Time: start.AddDate(0, 0, 8)},
+		{Time: start.AddDate(0, 0, 10), Price: 54.99},
+	}
+	data, error := Render(points, formatPrice)
+	if error != nil {
+		t.Fatal(error)
+	}
+	img, error := png.Decode(bytes.NewReader(data))
+	if error != nil {
+		t.Fatal(error)
+	}
+
+	//Same scale as Render: prices 49.99 to 69.99, padded by 10%
+	var low, high float32 = 49.99, 69.99
+	padding := (high - low) * 0.1
+	low, high = low-padding, high+padding
+	left, right, top, bottom := marginLeft, Width-marginRight, marginTop, Height-marginBottom
+	end := points[len(points)-1].Time
+	x := func(t time.Time) int {
+		return left + int(float64(right-left)*float64(t.Sub(start))/float64(end.Sub(start)))
+	}
+	y := func(price float32) int {
+		return bottom - int(float32(bottom-top)*(price-low)/(high-low))
+	}
+	//colorNear allows for a pixel of rounding
+	colorNear := func(px int, py int, c color.RGBA) bool {
+		for dx := -1; dx <= 1; dx++ {
+			for dy := -1; dy <= 1; dy++ {
+				r, g, b, _ := img.At(px+dx, py+dy).RGBA()
+				if uint8(r>>8) == c.R && uint8(g>>8) == c.G && uint8(b>>8) == c.B {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	dashes, gaps := 0, 0
+	for px := x(points[0].Time) + 2; px < x(points[1].Time)-2; px++ {
+		if colorNear(px, y(69.99), originalPriceColor) {
+			dashes++
+		} else {
+			gaps++
+		}
+	}
+	if dashes == 0 || gaps == 0 {
+		t.Errorf("Expected a dashed strike-through line, got %d dashed and %d blank pixels", dashes, gaps)
+	}
+	for _, p := range points[:2] {
+		if !colorNear(x(p.Time), y(p.Price), dealColor) {
+			t.Errorf("Expected a deal marker at %v", p.Time)
+		}
+	}
+	if colorNear(x(points[3].Time), y(points[3].Price), dealColor) {
+		t.Errorf("Expected no deal marker without an original price")
+	}
+	if !colorNear(x(points[2].Time), bottom-3, outOfStockColor) {
+		t.Errorf("Expected an out of stock tick at %v", points[2].Time)
+	}
+}
+
+func TestCurrencyLabels(t *testing.T) {
+	labels := []string{"$12.99", "€12.99", "£12.99", "¥1299", "₹12.99", "₺12.99", "CDN$12.99", "SEK 12.99"}
+	for _, label := range labels {
+		for _, r := range label {
+			if _, found := glyphs[r]; !found && r != ' ' {
+				t.Errorf("%s: %q is drawn as a space", label, r)
+			}
+		}
+
+		data, error := Render([]Point{{Time: time.Unix(0, 0), Price: 12.99}, {Time: time.Unix(3600, 0), Price: 10}}, func(float32) string { return label })
+		if error != nil {
+			t.Errorf("%s: %s", label, error)
+		} else if _, error := png.Decode(bytes.NewReader(data)); error != nil {
+			t.Errorf("%s: %s", label, error)
+		}
+	}
+
+	//The symbol alone leaves pixels
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	drawText(img, "€", 0, 0, 1, color.Black)
+	set := 0
+	for px := 0; px < glyphWidth; px++ {
+		for py := 0; py < glyphHeight; py++ {
+			if _, _, _, a := img.At(px, py).RGBA(); a > 0 {
+				set++
+			}
+		}
+	}
+	if set == 0 {
+		t.Error("Expected € to be drawn")
+	}
+}
@@ -0,0 +1,76 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+)
+
+//fillRect fills [x0, x1) x [y0, y1)
+func fillRect(img *image.RGBA, x0 int, y0 int, x1 int, y1 int, c color.Color) {
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			img.Set(x, y, c)
+		}
+	}
+}
+
+func fillCircle(img *image.RGBA, cx int, cy int, radius int, c color.Color) {
+	for y := -radius; y <= radius; y++ {
+		for x := -radius; x <= radius; x++ {
+			if x*x+y*y <= radius*radius {
+				img.Set(cx+x, cy+y, c)
+			}
+		}
+	}
+}
+
+//drawLine with Bresenham's algorithm, thickness pixels wide
+func drawLine(img *image.RGBA, x0 int, y0 int, x1 int, y1 int, thickness int, c color.Color) {
+	plotLine(x0, y0, x1, y1, func(x int, y int, _ int) {
+		fillRect(img, x, y, x+thickness, y+thickness, c)
+	})
+}
+
+//drawDashedLine alternates 6 drawn and 4 skipped pixels
+func drawDashedLine(img *image.RGBA, x0 int, y0 int, x1 int, y1 int, c color.Color) {
+	plotLine(x0, y0, x1, y1, func(x int, y int, step int) {
+		if step%10 < 6 {
+			img.Set(x, y, c)
+		}
+	})
+}
+
+//plotLine calls plot for every pixel of the line from (x0, y0) to (x1, y1), with the pixel's index
+func plotLine(x0 int, y0 int, x1 int, y1 int, plot func(x int, y int, step int)) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	difference := dx + dy
+	for step := 0; ; step++ {
+		plot(x0, y0, step)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		doubled := 2 * difference
+		if doubled >= dy {
+			difference += dy
+			x0 += sx
+		}
+		if doubled <= dx {
+			difference += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
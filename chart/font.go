@@ -0,0 +1,92 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+	"strings"
+)
+
+//glyphWidth and glyphHeight of the bitmap font, in font pixels
+const (
+	glyphWidth  = 3
+	glyphHeight = 5
+)
+
+//glyphs of a tiny 3x5 bitmap font. Each row is 3 characters, "#" is a set pixel. Letters are upper case only
+var glyphs = map[rune]string{
+	'0': "####.##.##.####",
+	'1': ".#.##..#..#.###",
+	'2': "###..#####..###",
+	'3': "###..####..####",
+	'4': "#.##.####..#..#",
+	'5': "####..###..####",
+	'6': "####..####.####",
+	'7': "###..#..#..#..#",
+	'8': "####.#####.####",
+	'9': "####.####..####",
+	'.': ".............#.",
+	',': "..........#.#..",
+	'-': "......###......",
+	'/': "..#..#.#.#..#..",
+	':': "....#.....#....",
+	'$': ".####..#..####.",
+	'€': ".######..###.##",
+	'£': ".###..####..###",
+	'¥': "#.#.#.###.#..#.",
+	'₹': "###..####.#...#",
+	'₺': ".#..####..#..##",
+	'%': "#.#..#.#.#..#.#",
+	'(': ".#.#..#..#...#.",
+	')': ".#...#..#..#.#.",
+	'A': ".#.#.#####.##.#",
+	'B': "##.#.###.#.###.",
+	'C': ".###..#..#...##",
+	'D': "##.#.##.##.###.",
+	'E': "####..##.#..###",
+	'F': "####..##.#..#..",
+	'G': ".###..#.##.#.##",
+	'H': "#.##.#####.##.#",
+	'I': "###.#..#..#.###",
+	'J': "..#..#..##.#.#.",
+	'K': "#.##.###.#.##.#",
+	'L': "#..#..#..#..###",
+	'M': "#.########.##.#",
+	'N': "##.#.##.##.##.#",
+	'O': ".#.#.##.##.#.#.",
+	'P': "##.#.###.#..#..",
+	'Q': ".#.#.##.###..##",
+	'R': "##.#.###.#.##.#",
+	'S': ".###...#...###.",
+	'T': "###.#..#..#..#.",
+	'U': "#.##.##.##.####",
+	'V': "#.##.##.##.#.#.",
+	'W': "#.##.########.#",
+	'X': "#.##.#.#.#.##.#",
+	'Y': "#.##.#.#..#..#.",
+	'Z': "###..#.#.#..###",
+}
+
+//textWidth of s drawn at scale, in image pixels
+func textWidth(s string, scale int) int {
+	count := len([]rune(s))
+	if count == 0 {
+		return 0
+	}
+	return (count*(glyphWidth+1) - 1) * scale
+}
+
+//drawText draws s with its top left corner at (x, y). Characters missing from the font are drawn as spaces
+func drawText(img *image.RGBA, s string, x int, y int, scale int, c color.Color) {
+	for _, r := range strings.ToUpper(s) {
+		glyph := glyphs[r]
+		for index, pixel := range glyph {
+			if pixel != '#' {
+				continue
+			}
+			px := x + (index%glyphWidth)*scale
+			py := y + (index/glyphWidth)*scale
+			fillRect(img, px, py, px+scale, py+scale, c)
+		}
+		x += (glyphWidth + 1) * scale
+	}
+}
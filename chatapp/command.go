@@ -0,0 +1,37 @@
+package chatapp
+
+import "strings"
+
+//CommandPrefix starts every command sent to the bot in a chat ("!amazing search mechanical keyboard")
+const CommandPrefix = "!amazing"
+
+//OnCommandCallback should be called when a message containing a command is received on a session
+type OnCommandCallback func(Session, *Command)
+
+//Command sent to the bot in a chat message
+type Command struct {
+	Name    string   //Lower cased name ("search", ...)
+	Args    []string //Words following the name
+	Message *Message //Message the command came from, use its Actions to respond
+}
+
+//IsCommand returns true when the content of a message is a command
+func IsCommand(content string) bool {
+	fields := strings.Fields(content)
+	return len(fields) > 0 && strings.EqualFold(fields[0], CommandPrefix)
+}
+
+//ParseCommand from the content of a message. found is false when the content isn't a command
+func ParseCommand(content string) (command *Command, found bool) {
+	if !IsCommand(content) {
+		return nil, false
+	}
+	fields := strings.Fields(content)[1:]
+	if len(fields) == 0 {
+		return &Command{Name: "help"}, true
+	}
+	return &Command{
+		Name: strings.ToLower(fields[0]),
+		Args: fields[1:],
+	}, true
+}
@@ -0,0 +1,130 @@
+package chatapp
+
+import (
+	"fmt"
+	"strings"
+)
+
+//MaxComparedProducts is the most products shown side by side in a comparison
+const MaxComparedProducts = 4
+
+//sameCurrency is true when every product with a price has it in the same currency, so prices can be ranked
+func sameCurrency(products []*Product) bool {
+	currency := ""
+	for _, p := range products {
+		if p.Price <= 0 {
+			continue
+		}
+		if len(currency) > 0 && p.Currency != currency {
+			return false
+		}
+		currency = p.Currency
+	}
+	return true
+}
+
+//bestValue returns the index of the cheapest in stock product (after coupons) rated within 0.3 stars of the best rated one
+//Returns -1 when no product is in stock with a price, or when prices are in different currencies
+func bestValue(products []*Product) int {
+	if !sameCurrency(products) {
+		return -1
+	}
+	var bestRating float32
+	for _, p := range products {
+		if !p.OutOfStock && p.Price > 0 && p.Rating > bestRating {
+			bestRating = p.Rating
+		}
+	}
+	best := -1
+	for index, p := range products {
+		if p.OutOfStock || p.Price <= 0 || p.Rating < bestRating-0.3 {
+			continue
+		}
+		if best == -1 || p.EffectivePrice() < products[best].EffectivePrice() {
+			best = index
+		}
+	}
+	return best
+}
+
+//comparisonTable lays out the price, rating, ratings count, stock and seller of each product in a monospace table
+//The best of each column is marked with *. Out of stock products can't have the lowest price, and prices in different currencies aren't ranked
+func comparisonTable(products []*Product) string {
+	const maxSellerLength = 18
+	lowestPrice, bestRating, mostRatings := -1, -1, -1
+	rankPrices := sameCurrency(products)
+	for index, p := range products {
+		if rankPrices && !p.OutOfStock && p.Price > 0 && (lowestPrice == -1 || p.EffectivePrice() < products[lowestPrice].EffectivePrice()) {
+			lowestPrice = index
+		}
+		if p.Rating > 0 && (bestRating == -1 || p.Rating > products[bestRating].Rating) {
+			bestRating = index
+		}
+		if p.RatingsCount > 0 && (mostRatings == -1 || p.RatingsCount > products[mostRatings].RatingsCount) {
+			mostRatings = index
+		}
+	}
+	mark := func(index int, best int) string {
+		if index == best {
+			return "*"
+		}
+		return ""
+	}
+
+	rows := [][]string{{"#", "Price", "Rating", "Ratings", "Stock", "Seller"}}
+	for index, p := range products {
+		price := "-"
+		if p.Price > 0 {
+			price = p.formatPrice(p.EffectivePrice())
+		}
+		seller := "-"
+		if len(p.Seller) > 0 {
+			seller = cutoffString(p.Seller, maxSellerLength, "...")
+		}
+		stock := "Yes"
+		if p.OutOfStock {
+			stock = "No"
+		}
+		rows = append(rows, []string{
+			fmt.Sprint(index + 1),
+			price + mark(index, lowestPrice),
+			fmt.Sprintf("%.1f", p.Rating) + mark(index, bestRating),
+			fmt.Sprint(p.RatingsCount) + mark(index, mostRatings),
+			stock,
+			seller,
+		})
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for column, cell := range row {
+			if length := len([]rune(cell)); length > widths[column] {
+				widths[column] = length
+			}
+		}
+	}
+	lines := []string{}
+	for _, row := range rows {
+		cells := []string{}
+		for column, cell := range row {
+			cells = append(cells, cell+strings.Repeat(" ", widths[column]-len([]rune(cell))))
+		}
+		lines = append(lines, strings.TrimRight(strings.Join(cells, "  "), " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+//comparisonTitles lists the numbered titles of the products, linked with link(url, title), and which one is the best value
+func comparisonTitles(products []*Product, link func(url string, title string) string, bold string) string {
+	const maxTitleLength = 80
+	best := bestValue(products)
+	lines := []string{}
+	for index, p := range products {
+		line := fmt.Sprintf("%d. %s", index+1, link(p.URL.String(), cutoffString(p.Title, maxTitleLength, "...")))
+		if index == best {
+			line = fmt.Sprintf("%s ⭐ %sBest value%s", line, bold, bold)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
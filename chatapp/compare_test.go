@@ -0,0 +1,122 @@
+package chatapp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBestValue(t *testing.T) {
+	testTable := []struct {
+		name     string
+		products []*Product
+		expected int
+	}{
+		{
+			name:     "cheapest within 0.3 stars of the best rated",
+			products: []*Product{{Price: 30, Rating: 4.8}, {Price: 20, Rating: 4.6}, {Price: 10, Rating: 4.0}},
+			expected: 1,
+		},
+		{
+			name:     "tie keeps the first",
+			products: []*Product{{Price: 20, Rating: 4.5}, {Price: 20, Rating: 4.5}},
+			expected: 0,
+		},
+		{
+			name:     "coupon lowers the price",
+			products: []*Product{{Price: 20, Rating: 4.5}, {Price: 25, Rating: 4.5, Coupon: &Coupon{Amount: 10}}},
+			expected: 1,
+		},
+		{
+			name:     "out of stock is skipped, even when best rated",
+			products: []*Product{{Price: 10, Rating: 5, OutOfStock: true}, {Price: 20, Rating: 4.0}},
+			expected: 1,
+		},
+		{
+			name:     "missing price is skipped",
+			products: []*Product{{Rating: 5}, {Price: 20, Rating: 4.0}},
+			expected: 1,
+		},
+		{
+			name:     "prices in different currencies",
+			products: []*Product{{Price: 30, Currency: "USD", Rating: 4.5}, {Price: 20, Currency: "EUR", Rating: 4.5}},
+			expected: -1,
+		},
+		{
+			name:     "missing price in another currency",
+			products: []*Product{{Price: 30, Currency: "USD", Rating: 4.5}, {Currency: "EUR", Rating: 5}, {Price: 20, Currency: "USD", Rating: 4.5}},
+			expected: 2,
+		},
+		{
+			name:     "nothing in stock with a price",
+			products: []*Product{{Rating: 5}, {Price: 20, OutOfStock: true}},
+			expected: -1,
+		},
+	}
+
+	for _, test := range testTable {
+		if result := bestValue(test.products); result != test.expected {
+			t.Errorf("%v: Expected: %d Result: %d", test.name, test.expected, result)
+		}
+	}
+}
+
+func TestComparisonTable(t *testing.T) {
+	testTable := []struct {
+		name     string
+		products []*Product
+		expected string
+	}{
+		{
+			name: "best of each column",
+			products: []*Product{
+				{Price: 20, Currency: "USD", Rating: 4.5, RatingsCount: 100, Seller: "Amazon.com"},
+				{Price: 10, Currency: "USD", Rating: 4.7, RatingsCount: 50},
+			},
+			expected: "" +
+				"#  Price    Rating  Ratings  Stock  Seller\n" +
+				"1  $20.00   4.5     100*     Yes    Amazon.com\n" +
+				"2  $10.00*  4.7*    50       Yes    -",
+		},
+		{
+			name: "ties mark the first",
+			products: []*Product{
+				{Price: 10, Currency: "USD", Rating: 4.5, RatingsCount: 10},
+				{Price: 10, Currency: "USD", Rating: 4.5, RatingsCount: 10},
+			},
+			expected: "" +
+				"#  Price    Rating  Ratings  Stock  Seller\n" +
+				"1  $10.00*  4.5*    10*      Yes    -\n" +
+				"2  $10.00   4.5     10       Yes    -",
+		},
+		{
+			name: "out of stock and missing price",
+			products: []*Product{
+				{Price: 5, Currency: "USD", OutOfStock: true},
+				{Rating: 4},
+				{Price: 15, Currency: "USD"},
+			},
+			expected: "" +
+				"#  Price    Rating  Ratings  Stock  Seller\n" +
+				"1  $5.00    0.0     0        No     -\n" +
+				"2  -        4.0*    0        Yes    -\n" +
+				"3  $15.00*  0.0     0        Yes    -",
+		},
+		{
+			name: "prices in different currencies",
+			products: []*Product{
+				{Price: 20, Currency: "USD", Rating: 4.5},
+				{Price: 10, Currency: "EUR", Rating: 4.7},
+			},
+			expected: "" +
+				"#  Price   Rating  Ratings  Stock  Seller\n" +
+				"1  $20.00  4.5     0        Yes    -\n" +
+				"2  €10.00  4.7*    0        Yes    -",
+		},
+	}
+
+	for _, test := range testTable {
+		if result := comparisonTable(test.products); result != test.expected {
+			t.Errorf("%v: Expected:\n%v\nResult:\n%v", test.name, test.expected, strings.TrimSpace(result))
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package chatapp
 
 import (
+	"bytes"
 	"fmt"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -10,6 +12,8 @@ import (
 type Discord struct {
 	session      *discordgo.Session
 	problemEmoji string
+	trackEmoji   string //Added to product embeds once OnTrackRequest is set up
+	galleries    *galleries
 }
 
 type discordMessageActions struct {
@@ -26,21 +30,54 @@ func (a *discordMessageActions) Remove() error {
 
 //RespondWithProduct implementation for Actions
 func (a *discordMessageActions) RespondWithProduct(p *Product) (string, error) {
-	embed := a.discord.toEmbed(p, a.message.Author.ID)
-	m, error := a.session.ChannelMessageSendEmbed(a.message.ChannelID, embed)
-	a.session.MessageReactionAdd(m.ChannelID, m.ID, a.discord.problemEmoji)
+	m, error := a.session.ChannelMessageSendComplex(a.message.ChannelID, a.discord.toMessageSend(p, a.message.Author.ID))
 	if error != nil {
 		return "", error
 	}
+	a.session.MessageReactionAdd(m.ChannelID, m.ID, a.discord.problemEmoji)
+	if len(a.discord.trackEmoji) > 0 {
+		a.session.MessageReactionAdd(m.ChannelID, m.ID, a.discord.trackEmoji)
+	}
+	a.discord.galleries.add(m.ID, p, a.message.Author.ID)
 	return discordMessageToID(m.ChannelID, m.ID), error
 }
 
+//RespondWithText implementation for Actions
+func (a *discordMessageActions) RespondWithText(text string) (string, error) {
+	m, error := a.session.ChannelMessageSend(a.message.ChannelID, text)
+	if error != nil {
+		return "", error
+	}
+	return discordMessageToID(m.ChannelID, m.ID), nil
+}
+
+//RespondWithProductList implementation for Actions
+func (a *discordMessageActions) RespondWithProductList(title string, products []*Product) (string, error) {
+	m, error := a.session.ChannelMessageSendEmbed(a.message.ChannelID, a.discord.toListEmbed(title, products))
+	if error != nil {
+		return "", error
+	}
+	return discordMessageToID(m.ChannelID, m.ID), nil
+}
+
+//RespondWithComparison implementation for Actions
+func (a *discordMessageActions) RespondWithComparison(products []*Product) (string, error) {
+	m, error := a.session.ChannelMessageSendEmbed(a.message.ChannelID, a.discord.toComparisonEmbed(products))
+	if error != nil {
+		return "", error
+	}
+	return discordMessageToID(m.ChannelID, m.ID), nil
+}
+
 //NewDiscordSession to setup hooks to events
 func NewDiscordSession(s *discordgo.Session) *Discord {
-	return &Discord{
+	db := &Discord{
 		session:      s,
 		problemEmoji: "🇫", //For those on dark themed editors, it's that blue [F] emoji.
+		galleries:    newGalleries(),
 	}
+	s.AddHandler(db.onGalleryButton)
+	return db
 }
 
 func discordMessageToID(channelID string, messageID string) string {
@@ -50,6 +87,8 @@ func discordMessageToID(channelID string, messageID string) string {
 func (db *Discord) createMessageFromDiscordMessage(s *discordgo.Session, m *discordgo.Message) *Message {
 	return &Message{
 		MessageIsFromThisBot: m.Author.ID == s.State.User.ID,
+		AuthorID:             m.Author.ID,
+		ChannelID:            m.ChannelID,
 		Content:              m.Content,
 		ID:                   discordMessageToID(m.ChannelID, m.ID),
 		Actions: &discordMessageActions{
@@ -77,6 +116,53 @@ func (db *Discord) OnProductProblemReport(cb OnProductProblemReportCallback) err
 	return nil
 }
 
+//OnTrackRequest implements Session. Users react to product embeds with 🔔
+func (db *Discord) OnTrackRequest(cb OnTrackRequestCallback) error {
+	db.trackEmoji = "🔔"
+	db.session.AddHandler(func(s *discordgo.Session, m *discordgo.MessageReactionAdd) {
+		if m.UserID == s.State.User.ID || m.Emoji.Name != db.trackEmoji {
+			return
+		}
+		cb(db, &TrackRequest{
+			MessageID: discordMessageToID(m.ChannelID, m.MessageID),
+			UserID:    m.UserID,
+			ChannelID: m.ChannelID,
+		})
+	})
+	return nil
+}
+
+//Platform implements Notifier
+func (db *Discord) Platform() string {
+	return "discord"
+}
+
+//Notify implements Notifier
+func (db *Discord) Notify(n *Notification) error {
+	channelID := n.ChannelID
+	content := n.Text
+	if len(channelID) == 0 {
+		channel, error := db.session.UserChannelCreate(n.UserID)
+		if error != nil {
+			return error
+		}
+		channelID = channel.ID
+	} else {
+		content = fmt.Sprintf("<@%s> %s", n.UserID, n.Text)
+	}
+
+	message := &discordgo.MessageSend{}
+	if n.Product != nil {
+		message = db.toMessageSend(n.Product, n.UserID)
+	}
+	message.Content = content
+	m, error := db.session.ChannelMessageSendComplex(channelID, message)
+	if error == nil && n.Product != nil {
+		db.galleries.add(m.ID, n.Product, n.UserID)
+	}
+	return error
+}
+
 //OnMessage implements Session
 func (db *Discord) OnMessage(cb OnMessageCallback) error {
 	db.session.AddHandler(func(s *discordgo.Session, m *discordgo.MessageCreate) {
@@ -85,32 +171,62 @@ func (db *Discord) OnMessage(cb OnMessageCallback) error {
 	return nil
 }
 
+//OnCommand implements Session
+func (db *Discord) OnCommand(cb OnCommandCallback) error {
+	db.session.AddHandler(func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		if m.Author.ID == s.State.User.ID {
+			return
+		}
+		if command, found := ParseCommand(m.Content); found {
+			command.Message = db.createMessageFromDiscordMessage(s, m.Message)
+			cb(db, command)
+		}
+	})
+	return nil
+}
+
 func cutoffString(input string, max int, replacement string) string {
-	if len(input) > max {
-		return input[:max] + replacement
+	if runes := []rune(input); len(runes) > max {
+		return string(runes[:max]) + replacement
 	}
 	return input
 }
 
-func (db *Discord) toEmbed(product *Product, authorID string) *discordgo.MessageEmbed {
+//priceChartFileName of the price history chart attached to product embeds
+const priceChartFileName = "price-history.png"
+
+//toMessageSend renders the product as an embed, attaching its price chart. The chart is the embed's image unless the product has a gallery
+func (db *Discord) toMessageSend(product *Product, authorID string) *discordgo.MessageSend {
+	message := &discordgo.MessageSend{Embed: db.toEmbed(product, authorID, 0), Components: galleryButtons(product)}
+	if len(product.PriceChart) > 0 {
+		message.Files = []*discordgo.File{{
+			Name:        priceChartFileName,
+			ContentType: "image/png",
+			Reader:      bytes.NewReader(product.PriceChart),
+		}}
+		if message.Embed.Image == nil {
+			message.Embed.Image = &discordgo.MessageEmbedImage{URL: "attachment://" + priceChartFileName}
+		}
+	}
+	return message
+}
+
+//toEmbed renders the product, showing Images[imageIndex] when the product has a gallery
+func (db *Discord) toEmbed(product *Product, authorID string, imageIndex int) *discordgo.MessageEmbed {
 	const maxContentLength = 150
 	const replacementContent = "..."
+	const maxVariants = 8
 
 	title := product.Title
 	if len(title) == 0 {
 		title = "*Title not found*"
 	}
 
-	priceText := fmt.Sprintf("%.2f", product.Price)
-	if product.OriginalPrice > 0 {
-		savings := product.OriginalPrice - product.Price
-		percentOff := savings / product.OriginalPrice * 100
-		priceText = fmt.Sprintf("~~%0.2f~~\n**%0.2f**\n*%.2f (%.0f%%) off*", product.OriginalPrice, product.Price, savings, percentOff)
-	}
+	priceText := product.priceText(markup{Bold: "**", Strike: "~~", Italic: "*"})
 	embed := discordgo.MessageEmbed{
 		Title:       title,
 		URL:         product.URL.String(),
-		Description: cutoffString(product.Description, maxContentLength, replacementContent),
+		Description: cutoffString(product.summary(), maxContentLength, replacementContent),
 		Thumbnail: &discordgo.MessageEmbedThumbnail{
 			URL: product.ImageURL,
 		},
@@ -120,20 +236,118 @@ func (db *Discord) toEmbed(product *Product, authorID string) *discordgo.Message
 				Value:  priceText,
 				Inline: true,
 			},
-			{
-				Name:   "Rating",
-				Value:  fmt.Sprintf("%.1f", product.Rating),
-				Inline: true,
-			},
-			{
-				Name:   "#Ratings",
-				Value:  fmt.Sprintf("%v", product.RatingsCount),
-				Inline: true,
-			},
 		},
 		Color: 0xFF9900,
 	}
 
+	if history := product.priceHistoryText(); len(history) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Price history",
+			Value:  history,
+			Inline: true,
+		})
+	}
+	embed.Fields = append(embed.Fields,
+		&discordgo.MessageEmbedField{
+			Name:   "Rating",
+			Value:  fmt.Sprintf("%.1f", product.Rating),
+			Inline: true,
+		},
+		&discordgo.MessageEmbedField{
+			Name:   "#Ratings",
+			Value:  fmt.Sprintf("%v", product.RatingsCount),
+			Inline: true,
+		},
+	)
+
+	for _, d := range product.kindDetails() {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   d.Name,
+			Value:  d.Value,
+			Inline: true,
+		})
+	}
+	if seller := product.sellerText(); len(seller) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Sold by",
+			Value:  seller,
+			Inline: true,
+		})
+	}
+	if shipping := product.shippingText(); len(shipping) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Shipping",
+			Value:  shipping,
+			Inline: true,
+		})
+	}
+
+	if offers := product.offersText(); len(offers) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Other sellers",
+			Value:  offers,
+			Inline: false,
+		})
+	}
+
+	if ranks := product.salesRanksText(); len(ranks) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Best Sellers Rank",
+			Value:  ranks,
+			Inline: false,
+		})
+	}
+	footer := []string{}
+	if len(product.Categories) > 0 {
+		footer = append(footer, product.categoryPath())
+	}
+	if len(product.Images) > 1 {
+		image := product.Images[imageIndex]
+		embed.Thumbnail = nil
+		embed.Image = &discordgo.MessageEmbedImage{URL: image.URL}
+		footer = append(footer, fmt.Sprintf("Image %d of %d", imageIndex+1, len(product.Images)))
+		if len(image.VideoURL) > 0 {
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+				Name:   "Video",
+				Value:  fmt.Sprintf("▶️ [Watch](%s)", image.VideoURL),
+				Inline: false,
+			})
+		}
+	}
+	if len(footer) > 0 {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: strings.Join(footer, " · ")}
+	}
+
+	if chart := product.histogramChart(); len(chart) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Ratings",
+			Value:  fmt.Sprintf("```\n%s\n```", chart),
+			Inline: false,
+		})
+	}
+	if product.TopPositiveReview != nil {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Top positive review",
+			Value:  reviewText(product.TopPositiveReview, maxContentLength, "**"),
+			Inline: false,
+		})
+	}
+	if product.TopCriticalReview != nil {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Top critical review",
+			Value:  reviewText(product.TopCriticalReview, maxContentLength, "**"),
+			Inline: false,
+		})
+	}
+
+	if len(product.Variants) > 1 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   fmt.Sprintf("Options (%s)", strings.Join(product.VariationDimensions, " / ")),
+			Value:  product.variantsText(maxVariants),
+			Inline: false,
+		})
+	}
+
 	if product.OutOfStock {
 		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
 			Name:   "Out Of Stock",
@@ -153,3 +367,37 @@ func (db *Discord) toEmbed(product *Product, authorID string) *discordgo.Message
 	})
 	return &embed
 }
+
+//toListEmbed lists products compactly, one line each
+func (db *Discord) toListEmbed(title string, products []*Product) *discordgo.MessageEmbed {
+	const maxTitleLength = 80
+	const replacementContent = "..."
+
+	lines := []string{}
+	for index, product := range products {
+		lines = append(lines, fmt.Sprintf("**%d.** [%s](%s)\n%s", index+1, cutoffString(product.Title, maxTitleLength, replacementContent), product.URL, product.listDetails()))
+	}
+
+	embed := discordgo.MessageEmbed{
+		Title:       title,
+		Description: strings.Join(lines, "\n"),
+		Color:       0xFF9900,
+	}
+	if len(products) > 0 && len(products[0].ImageURL) > 0 {
+		embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: products[0].ImageURL}
+	}
+	return &embed
+}
+
+//toComparisonEmbed shows products side by side in a table, under their numbered titles
+func (db *Discord) toComparisonEmbed(products []*Product) *discordgo.MessageEmbed {
+	link := func(url string, title string) string {
+		return fmt.Sprintf("[%s](%s)", title, url)
+	}
+	return &discordgo.MessageEmbed{
+		Title:       "Comparison",
+		Description: fmt.Sprintf("%s\n```\n%s\n```", comparisonTitles(products, link, "**"), comparisonTable(products)),
+		Footer:      &discordgo.MessageEmbedFooter{Text: "* Best of the column"},
+		Color:       0xFF9900,
+	}
+}
@@ -0,0 +1,60 @@
+package chatapp
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+//galleryButtons lets users page through the product's images, when it has more than one
+func galleryButtons(product *Product) []discordgo.MessageComponent {
+	if len(product.Images) < 2 {
+		return nil
+	}
+	return []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.Button{Emoji: discordgo.ComponentEmoji{Name: "⬅️"}, Style: discordgo.SecondaryButton, CustomID: previousImageID},
+		discordgo.Button{Emoji: discordgo.ComponentEmoji{Name: "➡️"}, Style: discordgo.SecondaryButton, CustomID: nextImageID},
+	}}}
+}
+
+//onGalleryButton edits the embed to show the previous or next image
+//Everyone sees the same message, so only the user who posted the link can page through it. Others are told so privately
+func (db *Discord) onGalleryButton(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionMessageComponent || i.Message == nil {
+		return
+	}
+	step := galleryStep(i.MessageComponentData().CustomID)
+	if step == 0 {
+		return
+	}
+	userID := ""
+	if i.Member != nil && i.Member.User != nil {
+		userID = i.Member.User.ID
+	} else if i.User != nil {
+		userID = i.User.ID
+	}
+
+	gallery := db.galleries.turn(i.Message.ID, userID, step)
+	switch {
+	case gallery == nil:
+		s.InteractionRespond(i.Interaction, ephemeralResponse(galleryExpiredText))
+	case gallery.authorID != userID:
+		s.InteractionRespond(i.Interaction, ephemeralResponse(fmt.Sprintf(galleryNotOwnerText, gallery.authorID)))
+	default:
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{
+				Embeds:     []*discordgo.MessageEmbed{db.toEmbed(gallery.product, gallery.authorID, gallery.index)},
+				Components: galleryButtons(gallery.product),
+			},
+		})
+	}
+}
+
+//ephemeralResponse is only shown to the user who pressed a button
+func ephemeralResponse(text string) *discordgo.InteractionResponse {
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Content: text, Flags: discordgo.MessageFlagsEphemeral},
+	}
+}
@@ -0,0 +1,78 @@
+package chatapp
+
+import "sync"
+
+//Button IDs of the gallery navigation, on Discord and Slack
+const (
+	previousImageID = "gallery-previous"
+	nextImageID     = "gallery-next"
+	//maxGalleries is how many product messages can be paged through at once. The oldest are forgotten first
+	maxGalleries = 200
+)
+
+//gallery is a product message whose images can be paged through with buttons, by the user who posted the link
+type gallery struct {
+	product  *Product
+	authorID string
+	index    int
+}
+
+//galleries holds the latest product messages with more than one image, by message ID
+type galleries struct {
+	mutex     sync.Mutex
+	galleries map[string]*gallery
+	order     []string
+}
+
+func newGalleries() *galleries {
+	return &galleries{galleries: make(map[string]*gallery)}
+}
+
+//add the message of a product posted for authorID. Products with a single image have nothing to page through
+func (g *galleries) add(messageID string, product *Product, authorID string) {
+	if len(product.Images) < 2 || len(messageID) == 0 {
+		return
+	}
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.galleries[messageID] = &gallery{product: product, authorID: authorID}
+	g.order = append(g.order, messageID)
+	if len(g.order) > maxGalleries {
+		delete(g.galleries, g.order[0])
+		g.order = g.order[1:]
+	}
+}
+
+//turn the gallery of a message by step images, returning a copy of the gallery to render
+//Returns nil when the gallery is unknown, and the gallery unturned when userID didn't post it
+func (g *galleries) turn(messageID string, userID string, step int) *gallery {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	gallery, found := g.galleries[messageID]
+	if !found {
+		return nil
+	}
+	if gallery.authorID == userID {
+		count := len(gallery.product.Images)
+		gallery.index = ((gallery.index+step)%count + count) % count
+	}
+	result := *gallery
+	return &result
+}
+
+//galleryStep of a navigation button, 0 for other buttons
+func galleryStep(buttonID string) int {
+	switch buttonID {
+	case previousImageID:
+		return -1
+	case nextImageID:
+		return 1
+	}
+	return 0
+}
+
+//Replies to users pressing the navigation buttons of a gallery they can't page through
+const (
+	galleryExpiredText  = "These images can't be paged through anymore, post the link again to see them"
+	galleryNotOwnerText = "Only <@%s> can page through these images. Post the link yourself to see them"
+)
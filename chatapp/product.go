@@ -1,16 +1,520 @@
 package chatapp
 
-import "net/url"
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
 
 //Product to be sent to a chat application
 type Product struct {
 	Title         string   `json:"title"`
 	Price         float32  `json:"price"`
 	ImageURL      string   `json:"imageURL"`
+	Images        []Image  `json:"images,omitempty"` //Every gallery image and video thumbnail
 	Description   string   `json:"description"`
 	RatingsCount  uint     `json:"ratingsCount"` //Number of ratings (the amound of people giving a product a star count)
 	Rating        float32  `json:"rating"`       //Rating percentage (0-5, 5 = five star rating, 4.5 would be 4 and half stars)
 	OutOfStock    bool     `json:"outOfStock"`
 	OriginalPrice float32  `json:"originalPrice"`
+	Currency      string   `json:"currency"` //ISO 4217 code of Price and OriginalPrice ("USD", "EUR", ...)
+	Brand         string   `json:"brand"`
+	Features      []string `json:"features"` //"About this item" feature bullets
+	Seller        string   `json:"seller"`   //Sold by
+	ShipsFrom     string   `json:"shipsFrom"`
+	Prime         bool     `json:"prime"`
+	FreeShipping  bool     `json:"freeShipping"`
 	URL           *url.URL `json:"url"`
+
+	RatingHistogram   [5]uint `json:"ratingHistogram"` //Percentage of ratings per star count. RatingHistogram[0] is 1 star, RatingHistogram[4] is 5 stars
+	TopPositiveReview *Review `json:"topPositiveReview,omitempty"`
+	TopCriticalReview *Review `json:"topCriticalReview,omitempty"`
+
+	Categories       []string    `json:"categories,omitempty"`       //Breadcrumb category path, broadest first
+	BestSellersRanks []SalesRank `json:"bestSellersRanks,omitempty"` //Broadest category first
+
+	VariationDimensions []string  `json:"variationDimensions,omitempty"` //Names of the dimensions Variants vary by ("Size", "Color")
+	Variants            []Variant `json:"variants,omitempty"`
+
+	Kind  ProductKind `json:"kind"`
+	Media *Media      `json:"media,omitempty"` //Book, Kindle, music and video fields. nil for KindGeneral
+
+	PriceHistory *PriceStats `json:"priceHistory,omitempty"` //Prices seen by the bot, nil when it doesn't keep a history
+	PriceChart   []byte      `json:"-"`                      //PNG chart of the prices seen by the bot, nil until it has seen enough
+
+	Offers []Offer `json:"offers,omitempty"` //Offers from every seller, when the offer listing was fetched
+
+	Coupon                *Coupon `json:"coupon,omitempty"`
+	Deal                  *Deal   `json:"deal,omitempty"`
+	SubscribeAndSavePrice float32 `json:"subscribeAndSavePrice,omitempty"` //0 when the product can't be subscribed to
+
+	Sources         map[string]string `json:"sources,omitempty"` //Field name -> parsing strategy that produced the value, shown in reports
+	SelectorVersion string            `json:"-"`                 //Version of the scraper's selectors that parsed the product, saved with reports
+}
+
+//Image of a product's gallery
+type Image struct {
+	URL      string `json:"url"`                //For videos, the video's thumbnail
+	VideoURL string `json:"videoURL,omitempty"` //Set when the image is the thumbnail of a video
+}
+
+//Review snippet of a product
+type Review struct {
+	Author string  `json:"author"`
+	Title  string  `json:"title"`
+	Body   string  `json:"body"`
+	Rating float32 `json:"rating"`
+}
+
+//SalesRank of a product in a category ("#3 in Mechanical Keyboards")
+type SalesRank struct {
+	Rank     uint   `json:"rank"`
+	Category string `json:"category"`
+}
+
+//Variant of a product (a size, colour, configuration, ...)
+type Variant struct {
+	Values      []string `json:"values"` //One value per Product.VariationDimensions ("Large", "Black")
+	Price       float32  `json:"price"`  //0 when unknown
+	Unavailable bool     `json:"unavailable"`
+	Selected    bool     `json:"selected"` //The variant the product was posted as
+	URL         *url.URL `json:"url"`
+}
+
+//ProductKind decides which fields the renderers lay out for a Product
+type ProductKind string
+
+//Kinds of products. Every kind other than KindGeneral has Product.Media
+const (
+	KindGeneral ProductKind = "general"
+	KindBook    ProductKind = "book"
+	KindKindle  ProductKind = "kindle"
+	KindMusic   ProductKind = "music"
+	KindVideo   ProductKind = "video"
+)
+
+//Media holds the fields of books, Kindle editions, music and video
+type Media struct {
+	Contributors []Contributor `json:"contributors,omitempty"`
+	Format       string        `json:"format"` //"Paperback", "Kindle Edition", "Vinyl", "Blu-ray", ...
+	Pages        uint          `json:"pages,omitempty"`
+	Publisher    string        `json:"publisher"` //Publisher, record label or studio
+	ReleaseDate  string        `json:"releaseDate"`
+	Language     string        `json:"language"`
+	ISBN10       string        `json:"isbn10,omitempty"`
+	ISBN13       string        `json:"isbn13,omitempty"`
+}
+
+//Contributor to a book or media ("Author", "Artist", "Narrator", "Director", ...)
+type Contributor struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+//PriceStats summarises the prices seen of a product
+type PriceStats struct {
+	Lowest        float32   `json:"lowest"`
+	Highest       float32   `json:"highest"`
+	Average30Days float32   `json:"average30Days"` //0 when no price was seen in the last 30 days
+	Observations  int       `json:"observations"`
+	Since         time.Time `json:"since"` //When the first price was seen
+}
+
+//Offer of a product by a seller, in the product's currency
+type Offer struct {
+	Seller    string  `json:"seller"`
+	Condition string  `json:"condition"` //"New", "Used - Like New", "Renewed", ...
+	Price     float32 `json:"price"`
+	Shipping  float32 `json:"shipping"` //0 when shipping is free or unknown
+	Prime     bool    `json:"prime"`
+}
+
+//Coupon that can be clipped on a product. Either Percent or Amount is set
+type Coupon struct {
+	Percent float32 `json:"percent,omitempty"`
+	Amount  float32 `json:"amount,omitempty"`
+}
+
+//Deal badge of a product ("Lightning Deal", "Limited time deal", ...)
+type Deal struct {
+	Badge  string    `json:"badge"`
+	EndsAt time.Time `json:"endsAt"` //Zero when unknown
+}
+
+//currencySymbols used when formatting prices. Currencies without a symbol are prefixed with their code
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"CAD": "CDN$",
+	"MXN": "MX$",
+	"BRL": "R$",
+	"AUD": "A$",
+	"SGD": "S$",
+	"GBP": "£",
+	"EUR": "€",
+	"JPY": "¥",
+	"INR": "₹",
+	"TRY": "₺",
+}
+
+//FormatPrice formats an amount in the currency ("USD", "EUR", ...), like $12.99 or €1299.99
+func FormatPrice(amount float32, currency string) string {
+	decimals := 2
+	if currency == "JPY" {
+		decimals = 0
+	}
+	symbol, found := currencySymbols[currency]
+	if !found && len(currency) > 0 {
+		symbol = currency + " "
+	}
+	return fmt.Sprintf("%s%.*f", symbol, decimals, amount)
+}
+
+//formatPrice formats an amount in the product's currency
+func (p *Product) formatPrice(amount float32) string {
+	return FormatPrice(amount, p.Currency)
+}
+
+//listDetails is a one line summary of the price and rating, for compact lists
+func (p *Product) listDetails() string {
+	details := []string{}
+	if p.Price > 0 {
+		details = append(details, p.formatPrice(p.Price))
+	}
+	if p.Rating > 0 {
+		details = append(details, fmt.Sprintf("%.1f★ (%v)", p.Rating, p.RatingsCount))
+	}
+	if p.OutOfStock {
+		details = append(details, "Out Of Stock")
+	}
+	return strings.Join(details, " · ")
+}
+
+//summary is the Description, or the feature bullets when a product has no description
+func (p *Product) summary() string {
+	if len(p.Description) > 0 || len(p.Features) == 0 {
+		return p.Description
+	}
+	return "• " + strings.Join(p.Features, "\n• ")
+}
+
+//sellerText describes who sells and ships the product ("" when unknown)
+func (p *Product) sellerText() string {
+	if len(p.ShipsFrom) == 0 || p.ShipsFrom == p.Seller {
+		return p.Seller
+	}
+	if len(p.Seller) == 0 {
+		return fmt.Sprintf("Ships from %s", p.ShipsFrom)
+	}
+	return fmt.Sprintf("%s (Ships from %s)", p.Seller, p.ShipsFrom)
+}
+
+//shippingText describes Prime and free shipping eligibility ("" when neither)
+func (p *Product) shippingText() string {
+	switch {
+	case p.Prime && p.FreeShipping:
+		return "Prime, FREE Shipping"
+	case p.Prime:
+		return "Prime"
+	case p.FreeShipping:
+		return "FREE Shipping"
+	}
+	return ""
+}
+
+//variantsText lists up to max variants, one per line ("Large / Black: $12.99")
+func (p *Product) variantsText(max int) string {
+	lines := []string{}
+	for index, v := range p.Variants {
+		if index >= max {
+			lines = append(lines, fmt.Sprintf("...and %d more", len(p.Variants)-max))
+			break
+		}
+		line := strings.Join(v.Values, " / ")
+		if v.Price > 0 {
+			line += ": " + p.formatPrice(v.Price)
+		}
+		if v.Unavailable {
+			line += " (unavailable)"
+		}
+		if v.Selected {
+			line += " ◀"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+//histogramChart draws RatingHistogram as text bars, 5 stars first ("" when there's no histogram)
+func (p *Product) histogramChart() string {
+	const width = 10
+	if p.RatingHistogram == [5]uint{} {
+		return ""
+	}
+	lines := []string{}
+	for stars := 5; stars >= 1; stars-- {
+		percent := p.RatingHistogram[stars-1]
+		filled := int(percent*width+50) / 100
+		if filled > width {
+			filled = width
+		}
+		lines = append(lines, fmt.Sprintf("%d★ %s%s %3d%%", stars, strings.Repeat("█", filled), strings.Repeat("░", width-filled), percent))
+	}
+	return strings.Join(lines, "\n")
+}
+
+//reviewText formats a review snippet, cutting its body off at max characters. bold is the chat's bold markup ("**", "*")
+func reviewText(r *Review, max int, bold string) string {
+	stars := int(r.Rating + 0.5)
+	text := fmt.Sprintf("%s%s %s%s%s", strings.Repeat("★", stars), strings.Repeat("☆", 5-stars), bold, r.Title, bold)
+	if len(r.Body) > 0 {
+		text += "\n" + cutoffString(r.Body, max, "...")
+	}
+	if len(r.Author) > 0 {
+		text += "\n— " + r.Author
+	}
+	return text
+}
+
+//salesRanksText lists the Best Sellers Ranks, one per line ("#3 in Mechanical Keyboards")
+func (p *Product) salesRanksText() string {
+	lines := []string{}
+	for _, r := range p.BestSellersRanks {
+		lines = append(lines, fmt.Sprintf("#%d in %s", r.Rank, r.Category))
+	}
+	return strings.Join(lines, "\n")
+}
+
+//categoryPath joins the categories ("Electronics › Computers & Accessories › Keyboards")
+func (p *Product) categoryPath() string {
+	return strings.Join(p.Categories, " › ")
+}
+
+//CouponDiscount is how much the Coupon takes off the Price
+func (p *Product) CouponDiscount() float32 {
+	if p.Coupon == nil {
+		return 0
+	}
+	discount := p.Coupon.Amount
+	if p.Coupon.Percent > 0 {
+		discount = p.Price * p.Coupon.Percent / 100
+	}
+	if discount > p.Price {
+		discount = p.Price
+	}
+	return discount
+}
+
+//EffectivePrice is the Price after clipping the Coupon
+func (p *Product) EffectivePrice() float32 {
+	return p.Price - p.CouponDiscount()
+}
+
+//markup of a chat application's text formatting
+type markup struct {
+	Bold   string
+	Strike string
+	Italic string
+}
+
+//priceText is the price breakdown: strike-through price, coupon, effective price, Subscribe & Save and deal badge
+func (p *Product) priceText(m markup) string {
+	lines := []string{}
+	if p.OriginalPrice > 0 {
+		savings := p.OriginalPrice - p.Price
+		percentOff := savings / p.OriginalPrice * 100
+		lines = append(lines,
+			m.Strike+p.formatPrice(p.OriginalPrice)+m.Strike,
+			m.Bold+p.formatPrice(p.Price)+m.Bold,
+			fmt.Sprintf("%s%s (%.0f%%) off%s", m.Italic, p.formatPrice(savings), percentOff, m.Italic))
+	} else {
+		lines = append(lines, p.formatPrice(p.Price))
+	}
+
+	if discount := p.CouponDiscount(); discount > 0 {
+		coupon := "Coupon: -" + p.formatPrice(discount)
+		if p.Coupon.Percent > 0 {
+			coupon += fmt.Sprintf(" (%.0f%%)", p.Coupon.Percent)
+		}
+		lines = append(lines, coupon, fmt.Sprintf("%sWith coupon: %s%s", m.Bold, p.formatPrice(p.EffectivePrice()), m.Bold))
+	}
+	if p.SubscribeAndSavePrice > 0 {
+		lines = append(lines, "Subscribe & Save: "+p.formatPrice(p.SubscribeAndSavePrice))
+	}
+	if p.Deal != nil {
+		lines = append(lines, "⚡ "+p.Deal.dealText(time.Now()))
+	}
+	return strings.Join(lines, "\n")
+}
+
+//dealText is the badge, with the time left when known ("Lightning Deal, ends in 3h 12m")
+func (d *Deal) dealText(now time.Time) string {
+	if d.EndsAt.IsZero() {
+		return d.Badge
+	}
+	left := d.EndsAt.Sub(now).Truncate(time.Minute)
+	if left <= 0 {
+		return d.Badge + ", ended"
+	}
+	hours := int(left.Hours())
+	minutes := int(left.Minutes()) % 60
+	if hours > 0 {
+		return fmt.Sprintf("%s, ends in %dh %dm", d.Badge, hours, minutes)
+	}
+	return fmt.Sprintf("%s, ends in %dm", d.Badge, minutes)
+}
+
+//detail is a named value shown in a product's fields
+type detail struct {
+	Name  string
+	Value string
+}
+
+//kindDetails are the fields laid out for the product's kind. General products show their brand
+func (p *Product) kindDetails() []detail {
+	details := []detail{}
+	add := func(name string, value string) {
+		if len(value) > 0 {
+			details = append(details, detail{Name: name, Value: value})
+		}
+	}
+	if p.Media == nil {
+		add("Brand", p.Brand)
+		return details
+	}
+
+	m := p.Media
+	pages := ""
+	if m.Pages > 0 {
+		pages = fmt.Sprintf("%d pages", m.Pages)
+	}
+	switch p.Kind {
+	case KindBook:
+		add("Author", m.contributorsText("Author", "Editor", "Illustrator", roleUnknown))
+		add("Format", m.Format)
+		add("Pages", pages)
+		add("Publisher", m.Publisher)
+		add("Published", m.ReleaseDate)
+		add("ISBN", m.isbn())
+	case KindKindle:
+		add("Author", m.contributorsText("Author", "Editor", "Illustrator", roleUnknown))
+		add("Print length", pages)
+		add("Publisher", m.Publisher)
+		add("Published", m.ReleaseDate)
+		add("Language", m.Language)
+	case KindMusic:
+		add("Artist", m.contributorsText("Artist", "Performer", "Composer", roleUnknown))
+		add("Format", m.Format)
+		add("Label", m.Publisher)
+		add("Released", m.ReleaseDate)
+	case KindVideo:
+		add("Starring", m.contributorsText("Actor", roleUnknown))
+		add("Director", m.contributorsText("Director"))
+		add("Format", m.Format)
+		add("Released", m.ReleaseDate)
+	}
+	return details
+}
+
+//roleUnknown matches contributors without a role. Each kind lists them under a single field
+const roleUnknown = ""
+
+//contributorsText joins the names of the contributors with any of the roles
+func (m *Media) contributorsText(roles ...string) string {
+	names := []string{}
+	for _, c := range m.Contributors {
+		for _, role := range roles {
+			if strings.EqualFold(c.Role, role) {
+				names = append(names, c.Name)
+				break
+			}
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+//isbn prefers the ISBN-13
+func (m *Media) isbn() string {
+	if len(m.ISBN13) > 0 {
+		return m.ISBN13
+	}
+	return m.ISBN10
+}
+
+//galleryImage is an image of a product's gallery, captioned with its position ("2 of 7", "▶ Video 3 of 7: <url>")
+type galleryImage struct {
+	URL     string
+	Caption string
+}
+
+//galleryImage returns Images[index], or nil when the product has a single image
+func (p *Product) galleryImage(index int) *galleryImage {
+	if len(p.Images) < 2 || index < 0 || index >= len(p.Images) {
+		return nil
+	}
+	image := p.Images[index]
+	caption := fmt.Sprintf("%d of %d", index+1, len(p.Images))
+	if len(image.VideoURL) > 0 {
+		caption = fmt.Sprintf("▶ Video %s: %s", caption, image.VideoURL)
+	}
+	return &galleryImage{URL: image.URL, Caption: caption}
+}
+
+//offerConditions orders the condition groups of offersText
+var offerConditions = []string{"New", "Renewed", "Used", "Collectible"}
+
+//offersText lists the cheapest offer (price + shipping) of each condition, one per line ("Used: $12.99 + $3.99 shipping (Seller)")
+func (p *Product) offersText() string {
+	cheapest := map[string]Offer{}
+	for _, o := range p.Offers {
+		condition := offerCondition(o.Condition)
+		if current, found := cheapest[condition]; !found || o.Price+o.Shipping < current.Price+current.Shipping {
+			cheapest[condition] = o
+		}
+	}
+
+	lines := []string{}
+	for _, condition := range offerConditions {
+		o, found := cheapest[condition]
+		if !found {
+			continue
+		}
+		line := fmt.Sprintf("%s: %s", condition, p.formatPrice(o.Price))
+		if o.Shipping > 0 {
+			line += " + " + p.formatPrice(o.Shipping) + " shipping"
+		}
+		if len(o.Seller) > 0 {
+			line += fmt.Sprintf(" (%s)", o.Seller)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+//offerCondition groups a condition by its first word ("Used - Like New" is "Used"). Offers without a condition are new
+func offerCondition(condition string) string {
+	words := strings.Fields(strings.Replace(condition, "-", " ", 1))
+	switch {
+	case len(words) == 0:
+		return "New"
+	case strings.EqualFold(words[0], "Refurbished"):
+		return "Renewed"
+	}
+	return strings.Title(strings.ToLower(words[0]))
+}
+
+//priceHistoryText shows the lowest, highest and 30-day average price ("" until the price was seen more than once)
+func (p *Product) priceHistoryText() string {
+	h := p.PriceHistory
+	if h == nil || h.Observations < 2 {
+		return ""
+	}
+	lines := []string{
+		"Lowest: " + p.formatPrice(h.Lowest),
+		"Highest: " + p.formatPrice(h.Highest),
+	}
+	if h.Average30Days > 0 {
+		lines = append(lines, "30-day avg: "+p.formatPrice(h.Average30Days))
+	}
+	return strings.Join(lines, "\n")
 }
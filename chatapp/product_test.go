@@ -0,0 +1,38 @@
+package chatapp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKindDetailsContributors(t *testing.T) {
+	testTable := []struct {
+		name     string
+		kind     ProductKind
+		media    Media
+		expected []detail
+	}{
+		{
+			name:     "book",
+			kind:     KindBook,
+			media:    Media{Contributors: []Contributor{{Name: "A. Author", Role: "Author"}, {Name: "Unknown"}, {Name: "A. Translator", Role: "Translator"}}},
+			expected: []detail{{Name: "Author", Value: "A. Author, Unknown"}},
+		},
+		{
+			name:  "video",
+			kind:  KindVideo,
+			media: Media{Contributors: []Contributor{{Name: "A. Actor", Role: "Actor"}, {Name: "Unknown"}, {Name: "A. Director", Role: "Director"}}},
+			expected: []detail{
+				{Name: "Starring", Value: "A. Actor, Unknown"},
+				{Name: "Director", Value: "A. Director"},
+			},
+		},
+	}
+
+	for _, test := range testTable {
+		product := Product{Kind: test.kind, Media: &test.media}
+		if result := product.kindDetails(); !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("%v: Expected: %+v Result: %+v", test.name, test.expected, result)
+		}
+	}
+}
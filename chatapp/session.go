@@ -6,21 +6,52 @@ type OnMessageCallback func(Session, *Message)
 //OnProductProblemReportCallback should be called when a user reports a message. Typically through a reaction
 type OnProductProblemReportCallback func(s Session, messageID string)
 
+//OnTrackRequestCallback should be called when a user asks to track a product the bot posted. Typically through a reaction
+type OnTrackRequestCallback func(s Session, r *TrackRequest)
+
+//TrackRequest of a user for a product message posted by the bot
+type TrackRequest struct {
+	MessageID string //ID returned by RespondWithProduct
+	UserID    string
+	ChannelID string
+}
+
 //Session handler of a chat
 type Session interface {
 	OnMessage(OnMessageCallback) error
 	OnProductProblemReport(OnProductProblemReportCallback) error
+	OnCommand(OnCommandCallback) error
+	OnTrackRequest(OnTrackRequestCallback) error
+}
+
+//Notifier is implemented by Sessions that can message users outside of a reply
+type Notifier interface {
+	Platform() string //Unique name of the chat application ("discord", "slack")
+	Notify(n *Notification) error
+}
+
+//Notification to a user
+type Notification struct {
+	UserID    string
+	ChannelID string //Channel to mention the user in. Empty sends a direct message
+	Text      string
+	Product   *Product //Optional, shown after Text
 }
 
 //Actions to perform on a chat message
 type Actions interface {
 	Remove() error
 	RespondWithProduct(*Product) (newMessageID string, e error)
+	RespondWithText(text string) (newMessageID string, e error)
+	RespondWithProductList(title string, products []*Product) (newMessageID string, e error)
+	RespondWithComparison(products []*Product) (newMessageID string, e error)
 }
 
 //Message from a chat
 type Message struct {
 	ID                   string //Unique ID of the message
+	AuthorID             string
+	ChannelID            string
 	Content              string
 	MessageIsFromThisBot bool //Is this our own message (used for ignoring messages)
 	Actions              Actions
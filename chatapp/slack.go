@@ -7,45 +7,113 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
-	"text/template"
 )
 
 type slackMessageActions struct {
-	event *slackMessage
-	slack *Slack
+	event     *slackMessage
+	slack     *Slack
+	onlyALink bool //The message is nothing but a link, Remove only deletes those
 }
 
-//Remove implementation for Actions
+//Remove implementation for Actions. Messages with more than a link are kept, and so are messages whose thread gets the replies
 func (a *slackMessageActions) Remove() error {
-	return nil
+	if !a.onlyALink || a.slack.threadReplies {
+		return nil
+	}
+	return a.slack.deleteMessage(a.event.ChannelID, a.event.TimeStamp)
+}
+
+//apiRequest calls a Web API method ("chat.postMessage") with the bot token
+func (s *Slack) apiRequest(method string, jsonData []byte) ([]byte, error) {
+	return s.apiRequestWithToken(s.token, method, jsonData)
 }
 
-func (s *Slack) apiRequest(url string, jsonData []byte) ([]byte, error) {
-	req, _ := http.NewRequest("POST", url, bytes.NewBuffer([]byte(jsonData)))
+//apiFormRequest calls a Web API method that only takes form arguments, with the bot token
+func (s *Slack) apiFormRequest(method string, form url.Values) ([]byte, error) {
+	req, _ := http.NewRequest("POST", s.apiURL+method, strings.NewReader(form.Encode()))
 	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", s.token))
+	req.Header.Add("Content-type", "application/x-www-form-urlencoded")
+	res, error := http.DefaultClient.Do(req)
+	if error != nil {
+		return nil, error
+	}
+
+	resData, error := ioutil.ReadAll(res.Body)
+	defer res.Body.Close()
+	return resData, error
+}
+
+func (s *Slack) apiRequestWithToken(token string, method string, jsonData []byte) ([]byte, error) {
+	req, _ := http.NewRequest("POST", s.apiURL+method, bytes.NewBuffer([]byte(jsonData)))
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	req.Header.Add("Content-type", "application/json")
-	res, _ := http.DefaultClient.Do(req)
+	res, error := http.DefaultClient.Do(req)
+	if error != nil {
+		return nil, error
+	}
 
 	resData, error := ioutil.ReadAll(res.Body)
 	defer res.Body.Close()
 	return resData, error
 }
 
+//WithThreadReplies posts replies in the thread of the message instead of the channel
+func WithThreadReplies() SlackOption {
+	return func(s *Slack) {
+		s.threadReplies = true
+	}
+}
+
+//WithErrorHandler is called with errors that can't be returned, like Socket Mode connection failures
+func WithErrorHandler(handler func(error)) SlackOption {
+	return func(s *Slack) {
+		s.errorHandler = handler
+	}
+}
+
+//inThread posts the reply in the thread of the message when replying in threads
+func (a *slackMessageActions) inThread(payload *slackPayload) *slackPayload {
+	payload.ThreadTimeStamp = a.threadTimeStamp()
+	return payload
+}
+
+//threadTimeStamp replies are posted in. Messages already in a thread are replied to in that thread. Empty when thread replies are off
+func (a *slackMessageActions) threadTimeStamp() string {
+	if !a.slack.threadReplies {
+		return ""
+	}
+	if len(a.event.ThreadTimeStamp) > 0 {
+		return a.event.ThreadTimeStamp
+	}
+	return a.event.TimeStamp
+}
+
 //RespondWithProduct implementation for Actions
 func (a *slackMessageActions) RespondWithProduct(p *Product) (string, error) {
 	e := a.event
 	s := a.slack
 
-	data := slackRichTextJSONFromProduct(e.ChannelID, e.UserID, p, s.reportReactionCode)
-	resData, _ := s.apiRequest("https://slack.com/api/chat.postMessage", []byte(data))
+	data, _ := json.Marshal(a.inThread(slackProductMessage(e.ChannelID, e.UserID, p, s.reportReactionCode, 0)))
+	resData, _ := s.apiRequest("chat.postMessage", data)
 
 	var responseMessage slackEventMessageContainer
 	json.Unmarshal(resData, &responseMessage)
 
 	id := responseMessage.Message.TimeStamp
 	channelID := responseMessage.Channel
+	s.galleries.add(id, p, e.UserID)
 	s.react(channelID, id, s.reportReactionCode)
+	if len(s.trackReactionCode) > 0 {
+		s.react(channelID, id, s.trackReactionCode)
+	}
+	if len(p.PriceChart) > 0 {
+		if error := s.uploadPriceChart(channelID, a.threadTimeStamp(), p); error != nil {
+			s.handleError(error)
+		}
+	}
 
 	if len(s.myID) == 0 {
 		s.myID = responseMessage.Message.UserID
@@ -53,6 +121,113 @@ func (a *slackMessageActions) RespondWithProduct(p *Product) (string, error) {
 	return id, nil
 }
 
+//RespondWithText implementation for Actions
+func (a *slackMessageActions) RespondWithText(text string) (string, error) {
+	return a.slack.postMessage(a.inThread(slackTextMessage(a.event.ChannelID, text)))
+}
+
+//RespondWithProductList implementation for Actions
+func (a *slackMessageActions) RespondWithProductList(title string, products []*Product) (string, error) {
+	return a.slack.postMessage(a.inThread(slackProductListMessage(a.event.ChannelID, title, products)))
+}
+
+//RespondWithComparison implementation for Actions
+func (a *slackMessageActions) RespondWithComparison(products []*Product) (string, error) {
+	return a.slack.postMessage(a.inThread(slackComparisonMessage(a.event.ChannelID, products)))
+}
+
+//uploadPriceChart shares the product's price chart in a channel (or a thread of it)
+func (s *Slack) uploadPriceChart(channelID string, threadTimeStamp string, p *Product) error {
+	_, error := s.uploadFile(priceChartFileName, "Price history: "+p.Title, p.PriceChart, channelID, threadTimeStamp)
+	return error
+}
+
+//uploadFile sends data to a URL from files.getUploadURLExternal, then shares it in a channel (or a thread of it) with files.completeUploadExternal
+//Without a channel the file is private to the app, and only shown where its ID is used (see newSlackFileImage)
+func (s *Slack) uploadFile(fileName string, title string, data []byte, channelID string, threadTimeStamp string) (fileID string, e error) {
+	resData, error := s.apiFormRequest("files.getUploadURLExternal", url.Values{
+		"filename": {fileName},
+		"length":   {strconv.Itoa(len(data))},
+	})
+	if error != nil {
+		return "", error
+	}
+	var upload struct {
+		OK        bool   `json:"ok"`
+		Error     string `json:"error"`
+		UploadURL string `json:"upload_url"`
+		FileID    string `json:"file_id"`
+	}
+	json.Unmarshal(resData, &upload)
+	if !upload.OK {
+		return "", fmt.Errorf("[Slack] files.getUploadURLExternal failed: %s", upload.Error)
+	}
+
+	res, error := http.Post(upload.UploadURL, "application/octet-stream", bytes.NewReader(data))
+	if error != nil {
+		return "", error
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("[Slack] Upload of %s failed: %s", fileName, res.Status)
+	}
+
+	files, _ := json.Marshal([]struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+	}{{upload.FileID, title}})
+	complete := url.Values{"files": {string(files)}}
+	if len(channelID) > 0 {
+		complete.Set("channel_id", channelID)
+	}
+	if len(threadTimeStamp) > 0 {
+		complete.Set("thread_ts", threadTimeStamp)
+	}
+	resData, error = s.apiFormRequest("files.completeUploadExternal", complete)
+	if error != nil {
+		return "", error
+	}
+	return upload.FileID, slackResponseError("files.completeUploadExternal", resData)
+}
+
+//checkedAPIRequest calls a Web API method, failing when Slack answers with an error
+func (s *Slack) checkedAPIRequest(method string, jsonData []byte) error {
+	resData, error := s.apiRequest(method, jsonData)
+	if error != nil {
+		return error
+	}
+	return slackResponseError(method, resData)
+}
+
+//slackResponseError is the error Slack answered a Web API method with, if any
+func slackResponseError(method string, resData []byte) error {
+	var response struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	json.Unmarshal(resData, &response)
+	if !response.OK {
+		return fmt.Errorf("[Slack] %s failed: %s", method, response.Error)
+	}
+	return nil
+}
+
+//postMessage with chat.postMessage, and returns the timestamp (ID) of the new message
+func (s *Slack) postMessage(payload *slackPayload) (string, error) {
+	data, error := json.Marshal(payload)
+	if error != nil {
+		return "", error
+	}
+	resData, error := s.apiRequest("chat.postMessage", data)
+	if error != nil {
+		return "", error
+	}
+
+	var responseMessage slackEventMessageContainer
+	json.Unmarshal(resData, &responseMessage)
+	return responseMessage.Message.TimeStamp, nil
+}
+
 type slackMessage struct {
 	BotID           string `json:"bot_id"`
 	Type            string `json:"type"`
@@ -69,10 +244,16 @@ type slackMessage struct {
 			} `json:"Elements"`
 		} `json:"Elements"`
 	} `json:"blocks"`
-	ChannelID string `json:"channel"`
-	TimeStamp string `json:"ts"`
-	Reaction  string `json:"reaction"`
-	Item      struct {
+	ChannelID       string `json:"channel"`
+	TimeStamp       string `json:"ts"`
+	ThreadTimeStamp string `json:"thread_ts"`
+	Reaction        string `json:"reaction"`
+	//link_shared events
+	Links            []slackSharedLink `json:"links"`
+	MessageTimeStamp string            `json:"message_ts"`
+	UnfurlID         string            `json:"unfurl_id"`
+	Source           string            `json:"source"`
+	Item             struct {
 		Type      string `json:"type"`
 		Channel   string `json:"channel"`
 		TimeStamp string `json:"ts"`
@@ -80,6 +261,7 @@ type slackMessage struct {
 }
 
 type slackEventMessageContainer struct {
+	TeamID    string       `json:"team_id"`
 	Channel   string       `json:"channel"`
 	TimeStamp string       `json:"ts"`
 	Token     string       `json:"token"`
@@ -99,12 +281,17 @@ func parseEventMessage(reader io.ReadCloser) (*slackEventMessageContainer, error
 	return &message, error
 }
 
+//maxSlackEventSize is the largest request body read from Slack. Events are a few KB
+const maxSlackEventSize = 1 << 20
+
 //Event type names
 const (
 	slackeventMessage       = "message"
 	slackeventReactionAdded = "reaction_added"
+	slackeventLinkShared    = "link_shared"
 )
 
+//slackEventHandlerFunc handles an event. w and r are nil for events received through Socket Mode
 type slackEventHandlerFunc func(e *slackEventMessageContainer, w http.ResponseWriter, r *http.Request)
 
 //Slack Session implementation
@@ -112,120 +299,175 @@ type Slack struct {
 	typeToHandler      map[string][]slackEventHandlerFunc
 	token              string
 	reportReactionCode string
+	trackReactionCode  string //Added to product messages once OnTrackRequest is set up
+	signingSecret      string //See WithSigningSecret. Without it, requests are refused unless allowUnsigned
+	allowUnsigned      bool   //See WithoutSignatureVerification
+	defaultMode        SlackMode
+	threadReplies      bool        //See WithThreadReplies
+	removalToken       string      //See WithRemovalToken
+	cannotRemove       int32       //Set to 1 once Slack refused to delete a message, see deleteMessage
+	appToken           string      //See WithSocketMode
+	errorHandler       func(error) //See WithErrorHandler
+	apiURL             string
+	workspaceModes     map[string]SlackMode //By team ID
+	galleries          *galleries           //Product messages whose images can be paged through
 	myID               string
 }
 
 //NewSlackSession returns a Slack session that implements chatapp.Session
-func NewSlackSession(token string, reportReactionCode string) *Slack {
+func NewSlackSession(token string, reportReactionCode string, options ...SlackOption) *Slack {
 	handlers := make(map[string][]slackEventHandlerFunc)
 	handlers[slackeventMessage] = []slackEventHandlerFunc{}
-	return &Slack{
+	s := &Slack{
 		typeToHandler:      handlers,
 		token:              token,
 		reportReactionCode: reportReactionCode,
+		workspaceModes:     make(map[string]SlackMode),
+		galleries:          newGalleries(),
+		apiURL:             "https://slack.com/api/",
+	}
+	for _, option := range options {
+		option(s)
 	}
+	return s
 }
 
-func slackRichTextJSONFromProduct(channelID string, senderID string, p *Product, negativeReaction string) string {
-	funcMap := template.FuncMap{
-		"url": func(p *Product) string {
-			return p.URL.String()
-		},
-		"escape": func(s string) string {
-			return strings.ReplaceAll(s, `"`, `\"`)
-		},
-		"price": func(p *Product) string {
-			if p.OriginalPrice > 0 {
-				savings := p.OriginalPrice - p.Price
-				percentOff := savings / p.OriginalPrice * 100
-				return fmt.Sprintf("~%0.2f~\n*%0.2f*\n_%.2f (%.0f%%) off_", p.OriginalPrice, p.Price, savings, percentOff)
-			}
-			return fmt.Sprintf("%.2f", p.Price)
-		},
-		"rating": func(p *Product) string {
-			return fmt.Sprintf("%.1f", p.Rating)
-		},
-		"cutoff": func(input string) string {
-			const max = 150
-			const replacement = "..."
-			if len(input) > max {
-				return input[:max] + replacement
-			}
-			return input
-		},
-		"reportReaction": func() string {
-			return negativeReaction
-		},
-		"senderID": func() string {
-			return senderID
-		},
+//slackNoGalleryButtons shows the first image of a product's gallery, without buttons to page through it
+const slackNoGalleryButtons = -1
+
+//slackProductMessage lays out a product, posted by senderID
+//The gallery shows Images[imageIndex] with buttons to page through it, see handleInteraction
+func slackProductMessage(channelID string, senderID string, p *Product, reportReaction string, imageIndex int) *slackPayload {
+	const maxSummaryLength = 150
+	title := slackEscape(p.Title)
+	blocks := []slackBlock{
+		newSlackSection(fmt.Sprintf("*<%s|%s>*\n%s", p.URL, title, slackEscape(cutoffString(p.summary(), maxSummaryLength, "...")))).withImage(p.ImageURL, p.Title),
+		newSlackDivider(),
+		newSlackFields(fmt.Sprintf("*Rating*\n%.1f", p.Rating), fmt.Sprintf("*#Ratings*\n%d", p.RatingsCount)),
 	}
-	//WARNING: Building JSON this way is at risk of failing randomly and cause validation errors at runtime
-	//TODO: Create an anonymous struct to represent this instead, and use json.Marshal
-	blocksTemplate, _ := template.New("blocks").Funcs(funcMap).Parse(`
-	"blocks": [
-		{
-			"type": "section",
-			"text": {
-				"type": "mrkdwn",
-				"text": "*<{{url . | escape}}|{{.Title | escape}}>*\n{{.Description | cutoff | escape}}"
-			},
-			"accessory": {
-				"type": "image",
-				"image_url": "{{.ImageURL | escape}}",
-				"alt_text": "{{.Title | escape}}"
-			}
-		},
-		{
-			"type": "divider"
-		},
-		{
-			"type": "section",
-			"fields": [
-				{
-					"type": "mrkdwn",
-					"text": "*Rating*\n{{rating .}}"
-				},
-				{
-					"type": "mrkdwn",
-					"text": "*#Ratings*\n{{.RatingsCount}}"
-				}
-			]
-		},
-		{
-			"type": "section",
-			"fields": [
-				{
-					"type": "mrkdwn",
-					"text": "*Price*\n{{price .}}"
-				}
-			]
-		},
-		{
-			"type": "divider"
-		},
-		{
-			"type": "context",
-			"elements": [
-				{
-					"type": "mrkdwn",
-					"text": "Product posted by <@{{senderID}}>\n*Something wrong with this result?*\nReact with :{{reportReaction}}: to report and we'll look into it!"
-				}
-			]
+
+	priceHistory := ""
+	if history := p.priceHistoryText(); len(history) > 0 {
+		priceHistory = "*Price history*\n" + slackEscape(history)
+	}
+	blocks = append(blocks, newSlackFields("*Price*\n"+slackEscape(p.priceText(markup{Bold: "*", Strike: "~", Italic: "_"})), priceHistory))
+
+	if imageIndex == slackNoGalleryButtons {
+		if image := p.galleryImage(0); image != nil {
+			blocks = append(blocks, newSlackImage(image.URL, image.Caption))
+		}
+	} else if image := p.galleryImage(imageIndex); image != nil {
+		blocks = append(blocks,
+			newSlackImage(image.URL, image.Caption),
+			newSlackActions(newSlackButton("◀ Previous", previousImageID), newSlackButton("Next ▶", nextImageID)),
+		)
+	}
+
+	sections := []string{}
+	if offers := p.offersText(); len(offers) > 0 {
+		sections = append(sections, "*Other sellers*\n"+slackEscape(offers))
+	}
+	sections = append(sections, slackRanksText(p), slackReviewsText(p))
+	if len(p.Variants) >= 2 {
+		sections = append(sections, fmt.Sprintf("*Options (%s)*\n%s", slackEscape(strings.Join(p.VariationDimensions, " / ")), slackEscape(p.variantsText(8))))
+	}
+	sections = append(sections, slackDetailsText(p))
+	for _, section := range sections {
+		if len(section) > 0 {
+			blocks = append(blocks, newSlackSection(section))
 		}
-	]
-	`)
+	}
+
+	if p.URL != nil {
+		blocks = append(blocks, newSlackActions(newSlackLinkButton("View product", p.URL.String(), "view-product")))
+	}
+	blocks = append(blocks,
+		newSlackDivider(),
+		newSlackContext(fmt.Sprintf("Product posted by <@%s>\n*Something wrong with this result?*\nReact with :%s: to report and we'll look into it!", senderID, reportReaction)),
+	)
+
+	return &slackPayload{
+		Channel: channelID,
+		Text:    slackTruncate(slackEscape(p.Title), slackMaxFallbackLength),
+		Blocks:  blocks,
+	}
+}
 
-	var buffer bytes.Buffer
-	blocksTemplate.Execute(&buffer, p)
+func slackDetailsText(p *Product) string {
+	details := []string{}
+	for _, d := range p.kindDetails() {
+		details = append(details, fmt.Sprintf("*%s:* %s", slackEscape(d.Name), slackEscape(d.Value)))
+	}
+	if seller := p.sellerText(); len(seller) > 0 {
+		details = append(details, fmt.Sprintf("*Sold by:* %s", slackEscape(seller)))
+	}
+	if shipping := p.shippingText(); len(shipping) > 0 {
+		details = append(details, fmt.Sprintf("*Shipping:* %s", slackEscape(shipping)))
+	}
+	return strings.Join(details, "\n")
+}
 
-	fullJSONString := fmt.Sprintf(`
-	{
-		"channel": "%s",
-		%s
-	}`, channelID, buffer.String())
+func slackRanksText(p *Product) string {
+	sections := []string{}
+	if ranks := p.salesRanksText(); len(ranks) > 0 {
+		sections = append(sections, "*Best Sellers Rank*\n"+slackEscape(ranks))
+	}
+	if len(p.Categories) > 0 {
+		sections = append(sections, "_"+slackEscape(p.categoryPath())+"_")
+	}
+	return strings.Join(sections, "\n")
+}
 
-	return fullJSONString
+func slackReviewsText(p *Product) string {
+	sections := []string{}
+	if chart := p.histogramChart(); len(chart) > 0 {
+		sections = append(sections, fmt.Sprintf("*Ratings*\n```%s```", chart))
+	}
+	if p.TopPositiveReview != nil {
+		sections = append(sections, "*Top positive review*\n"+slackEscape(reviewText(p.TopPositiveReview, 150, "*")))
+	}
+	if p.TopCriticalReview != nil {
+		sections = append(sections, "*Top critical review*\n"+slackEscape(reviewText(p.TopCriticalReview, 150, "*")))
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+//slackProductListMessage lists products compactly, one section (with a thumbnail) each
+func slackProductListMessage(channelID string, title string, products []*Product) *slackPayload {
+	blocks := []slackBlock{newSlackSection(fmt.Sprintf("*%s*", slackEscape(title)))}
+	for index, p := range products {
+		text := fmt.Sprintf("*%d.* <%s|%s>\n%s", index+1, p.URL, slackEscape(cutoffString(p.Title, 80, "...")), slackEscape(p.listDetails()))
+		blocks = append(blocks, newSlackSection(text).withImage(p.ImageURL, p.Title))
+	}
+	return &slackPayload{
+		Channel: channelID,
+		Text:    slackTruncate(slackEscape(title), slackMaxFallbackLength),
+		Blocks:  blocks,
+	}
+}
+
+//slackComparisonMessage shows products side by side in a table, under their numbered titles
+func slackComparisonMessage(channelID string, products []*Product) *slackPayload {
+	link := func(url string, title string) string {
+		return fmt.Sprintf("<%s|%s>", url, slackEscape(title))
+	}
+	return &slackPayload{
+		Channel: channelID,
+		Text:    "Comparison",
+		Blocks: []slackBlock{
+			newSlackSection("*Comparison*\n" + comparisonTitles(products, link, "*")),
+			newSlackSection(fmt.Sprintf("```%s```", slackEscape(comparisonTable(products)))),
+			newSlackContext("* Best of the column"),
+		},
+	}
+}
+
+//slackTextMessage is a message of only text (mrkdwn)
+func slackTextMessage(channelID string, text string) *slackPayload {
+	return &slackPayload{
+		Channel: channelID,
+		Text:    text,
+	}
 }
 
 func (s *Slack) react(channelID string, id string, reactionCode string) {
@@ -239,37 +481,83 @@ func (s *Slack) react(channelID string, id string, reactionCode string) {
 		ID:           id,
 	})
 
-	s.apiRequest("https://slack.com/api/reactions.add", data)
+	s.apiRequest("reactions.add", data)
 }
 
-//OnMessage implements Session
+//OnMessage implements Session. Workspaces in unfurl mode get their messages from link_shared events instead
 func (s *Slack) OnMessage(cb OnMessageCallback) error {
 	temp := s.typeToHandler[slackeventMessage]
 	s.typeToHandler[slackeventMessage] = append(temp, func(emc *slackEventMessageContainer, w http.ResponseWriter, r *http.Request) {
 		e := emc.Event
+		if IsCommand(e.Text) || s.modeOf(emc.TeamID) == SlackUnfurlMode {
+			return
+		}
 
+		//Links of the message, one per line so several can be compared
+		links := []string{}
 		for _, b := range e.Blocks {
 			for _, parentElement := range b.Elements {
 				for _, element := range parentElement.Elements {
-					cb(s, &Message{
-						ID:                   e.ClientMessageID,
-						Content:              element.URL,
-						MessageIsFromThisBot: len(emc.Message.BotID) != 0,
-						Actions: &slackMessageActions{
-							event: &e,
-							slack: s,
-						},
-					})
+					if len(element.URL) > 0 {
+						links = append(links, element.URL)
+					}
 				}
 			}
 		}
+		if len(links) == 0 {
+			return
+		}
+		cb(s, &Message{
+			ID:                   e.ClientMessageID,
+			AuthorID:             e.UserID,
+			ChannelID:            e.ChannelID,
+			Content:              strings.Join(links, "\n"),
+			MessageIsFromThisBot: len(emc.Message.BotID) != 0,
+			Actions: &slackMessageActions{
+				event:     &e,
+				slack:     s,
+				onlyALink: len(links) == 1 && slackTextIsOnlyALink(e.Text),
+			},
+		})
+	})
+	s.typeToHandler[slackeventLinkShared] = append(s.typeToHandler[slackeventLinkShared], s.onLinkShared(cb))
+	return nil
+}
+
+//slackTextIsOnlyALink checks for messages like "<https://www.amazon.com/dp/B00XBWBWBK>"
+func slackTextIsOnlyALink(text string) bool {
+	text = strings.TrimSpace(text)
+	return strings.HasPrefix(text, "<") && strings.HasSuffix(text, ">") && strings.Count(text, "<") == 1 && !strings.ContainsAny(text, " \n\t")
+}
+
+//OnCommand implements Session
+func (s *Slack) OnCommand(cb OnCommandCallback) error {
+	temp := s.typeToHandler[slackeventMessage]
+	s.typeToHandler[slackeventMessage] = append(temp, func(emc *slackEventMessageContainer, w http.ResponseWriter, r *http.Request) {
+		e := emc.Event
+		if len(e.BotID) != 0 {
+			return
+		}
+		if command, found := ParseCommand(e.Text); found {
+			command.Message = &Message{
+				ID:        e.ClientMessageID,
+				AuthorID:  e.UserID,
+				ChannelID: e.ChannelID,
+				Content:   e.Text,
+				Actions: &slackMessageActions{
+					event: &e,
+					slack: s,
+				},
+			}
+			cb(s, command)
+		}
 	})
 	return nil
 }
 
 //OnProductProblemReport implements Session
 func (s *Slack) OnProductProblemReport(cb OnProductProblemReportCallback) error {
-	temp := s.typeToHandler[slackeventMessage]
+	temp := s.typeToHandler[slackeventReactionAdded]
 	s.typeToHandler[slackeventReactionAdded] = append(temp, func(emc *slackEventMessageContainer, w http.ResponseWriter, r *http.Request) {
 		reaction := emc.Event.Reaction
 		reactionFromBot := emc.Event.UserID == s.myID
@@ -280,26 +568,116 @@ func (s *Slack) OnProductProblemReport(cb OnProductProblemReportCallback) error
 	return nil
 }
 
-//Start an HTTP server and listen for Slack events
+//OnTrackRequest implements Session. Users react to product messages with :bell:
+func (s *Slack) OnTrackRequest(cb OnTrackRequestCallback) error {
+	s.trackReactionCode = "bell"
+	temp := s.typeToHandler[slackeventReactionAdded]
+	s.typeToHandler[slackeventReactionAdded] = append(temp, func(emc *slackEventMessageContainer, w http.ResponseWriter, r *http.Request) {
+		e := emc.Event
+		if e.Reaction != s.trackReactionCode || e.UserID == s.myID {
+			return
+		}
+		cb(s, &TrackRequest{
+			MessageID: e.Item.TimeStamp,
+			UserID:    e.UserID,
+			ChannelID: e.Item.Channel,
+		})
+	})
+	return nil
+}
+
+//Platform implements Notifier
+func (s *Slack) Platform() string {
+	return "slack"
+}
+
+//Notify implements Notifier. Direct messages are posted to the user's ID, which Slack delivers in the app's messages tab
+func (s *Slack) Notify(n *Notification) error {
+	channelID := n.ChannelID
+	text := n.Text
+	if len(channelID) == 0 {
+		channelID = n.UserID
+	} else {
+		text = fmt.Sprintf("<@%s> %s", n.UserID, n.Text)
+	}
+
+	if _, error := s.postMessage(slackTextMessage(channelID, text)); error != nil {
+		return error
+	}
+	if n.Product == nil {
+		return nil
+	}
+	id, error := s.postMessage(slackProductMessage(channelID, n.UserID, n.Product, s.reportReactionCode, 0))
+	if error != nil {
+		return error
+	}
+	s.galleries.add(id, n.Product, n.UserID)
+	if len(n.Product.PriceChart) > 0 {
+		return s.uploadPriceChart(channelID, "", n.Product)
+	}
+	return nil
+}
+
+//Start an HTTP server and listen for Slack events. With Socket Mode (see WithSocketMode) events come through a WebSocket instead, and port is unused
+//The server isn't started without a signing secret (ErrMissingSigningSecret), unless WithoutSignatureVerification
 func (s *Slack) Start(port string) {
+	if len(s.appToken) > 0 {
+		s.runSocketMode(make(chan struct{}))
+		return
+	}
+	if len(s.signingSecret) == 0 && !s.allowUnsigned {
+		s.handleError(ErrMissingSigningSecret)
+		return
+	}
 	http.ListenAndServe(port, s)
 }
 
 //ServeHTTP to implement http.Handler
 func (s *Slack) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	message, error := parseEventMessage(r.Body)
+	body, error := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxSlackEventSize))
+	r.Body.Close()
+	if error != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	if error := s.verify(r.Header, body); error != nil {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	//Interactivity (buttons) is posted as a form, with the interaction as JSON in payload
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		form, error := url.ParseQuery(string(body))
+		if error != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		s.handleInteraction([]byte(form.Get("payload")))
+		return
+	}
+
+	message, error := parseEventMessage(ioutil.NopCloser(bytes.NewReader(body)))
 
 	if error != nil {
 		return
 	}
 
+	if !s.dispatch(message, w, r) {
+		w.Write([]byte(message.Challenge))
+	}
+}
+
+//dispatch an event to the handlers of its type. Returns false when there's none
+func (s *Slack) dispatch(message *slackEventMessageContainer, w http.ResponseWriter, r *http.Request) bool {
 	handlers := s.typeToHandler[message.Event.Type]
+	for _, h := range handlers {
+		h(message, w, r)
+	}
+	return handlers != nil
+}
 
-	if handlers != nil {
-		for _, h := range handlers {
-			h(message, w, r)
-		}
-	} else {
-		w.Write([]byte(message.Challenge))
+func (s *Slack) handleError(e error) {
+	if s.errorHandler != nil {
+		s.errorHandler(e)
 	}
 }
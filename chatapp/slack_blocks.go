@@ -0,0 +1,211 @@
+package chatapp
+
+import "strings"
+
+//Block Kit model of Slack messages, see https://api.slack.com/reference/block-kit
+//Marshalled with encoding/json, so any text (quotes, backslashes, control characters) is safe to put in
+
+//Limits of Block Kit text lengths, in characters. Longer text fails the whole message
+const (
+	slackMaxTextLength      = 3000 //Section text
+	slackMaxFieldLength     = 2000 //Section fields, alt text and image titles
+	slackMaxButtonLength    = 75
+	slackMaxFallbackLength  = 4000 //Message text shown in notifications
+	slackMaxContextElements = 10
+)
+
+//Block Kit types
+const (
+	slackTypeMarkdown  = "mrkdwn"
+	slackTypePlainText = "plain_text"
+	slackTypeImage     = "image" //Image blocks and image elements
+	slackTypeButton    = "button"
+	slackTypeSection   = "section"
+	slackTypeContext   = "context"
+	slackTypeDivider   = "divider"
+	slackTypeActions   = "actions"
+)
+
+//slackPayload of chat.postMessage
+type slackPayload struct {
+	Channel         string       `json:"channel"`
+	Text            string       `json:"text"` //Shown in notifications, and by clients that can't show blocks
+	Blocks          []slackBlock `json:"blocks,omitempty"`
+	ThreadTimeStamp string       `json:"thread_ts,omitempty"`
+}
+
+//slackBlock is one of the slack*Block types
+type slackBlock interface {
+	blockType() string
+}
+
+//slackText object. Markdown ("mrkdwn") or plain text
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+//slackImageElement is an image inside a section (its accessory) or a context block
+type slackImageElement struct {
+	Type     string `json:"type"`
+	ImageURL string `json:"image_url"`
+	AltText  string `json:"alt_text"`
+}
+
+//slackButtonElement links to a URL, or sends its action ID to the app (see handleInteraction)
+type slackButtonElement struct {
+	Type     string     `json:"type"`
+	Text     *slackText `json:"text"`
+	URL      string     `json:"url,omitempty"`
+	ActionID string     `json:"action_id,omitempty"`
+}
+
+//slackSectionBlock of text, fields shown in two columns, or both
+type slackSectionBlock struct {
+	Type      string             `json:"type"`
+	Text      *slackText         `json:"text,omitempty"`
+	Fields    []*slackText       `json:"fields,omitempty"`
+	Accessory *slackImageElement `json:"accessory,omitempty"`
+}
+
+//slackImageBlock is a full width image, from a URL or a file uploaded to Slack
+type slackImageBlock struct {
+	Type      string         `json:"type"`
+	ImageURL  string         `json:"image_url,omitempty"`
+	SlackFile *slackFileItem `json:"slack_file,omitempty"`
+	AltText   string         `json:"alt_text"`
+	Title     *slackText     `json:"title,omitempty"`
+}
+
+//slackFileItem refers to a file uploaded to Slack
+type slackFileItem struct {
+	ID string `json:"id"`
+}
+
+//slackContextBlock of small text and images. Elements are *slackText or *slackImageElement
+type slackContextBlock struct {
+	Type     string        `json:"type"`
+	Elements []interface{} `json:"elements"`
+}
+
+//slackDividerBlock is a horizontal line
+type slackDividerBlock struct {
+	Type string `json:"type"`
+}
+
+//slackActionsBlock of buttons
+type slackActionsBlock struct {
+	Type     string                `json:"type"`
+	Elements []*slackButtonElement `json:"elements"`
+}
+
+func (b *slackSectionBlock) blockType() string { return b.Type }
+func (b *slackImageBlock) blockType() string   { return b.Type }
+func (b *slackContextBlock) blockType() string { return b.Type }
+func (b *slackDividerBlock) blockType() string { return b.Type }
+func (b *slackActionsBlock) blockType() string { return b.Type }
+
+//slackEscape the characters Slack treats as markup (links, mentions) in text from products
+//See https://api.slack.com/reference/surfaces/formatting#escaping
+func slackEscape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+//slackTruncate text to max characters
+func slackTruncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+//slackMarkdown text. It isn't escaped, use slackEscape on text from products
+func slackMarkdown(text string) *slackText {
+	return &slackText{Type: slackTypeMarkdown, Text: slackTruncate(text, slackMaxTextLength)}
+}
+
+func slackPlainText(text string, max int) *slackText {
+	return &slackText{Type: slackTypePlainText, Text: slackTruncate(text, max)}
+}
+
+func newSlackSection(text string) *slackSectionBlock {
+	return &slackSectionBlock{Type: slackTypeSection, Text: slackMarkdown(text)}
+}
+
+//newSlackFields is a section of fields. Empty fields are left out
+func newSlackFields(fields ...string) *slackSectionBlock {
+	section := &slackSectionBlock{Type: slackTypeSection}
+	for _, field := range fields {
+		if len(field) > 0 {
+			section.Fields = append(section.Fields, &slackText{Type: slackTypeMarkdown, Text: slackTruncate(field, slackMaxFieldLength)})
+		}
+	}
+	return section
+}
+
+//withImage adds an image to the right of the section. Nothing is added without a URL, Slack refuses empty ones
+func (b *slackSectionBlock) withImage(url string, altText string) *slackSectionBlock {
+	if len(url) > 0 {
+		b.Accessory = &slackImageElement{Type: slackTypeImage, ImageURL: url, AltText: slackTruncate(altText, slackMaxFieldLength)}
+	}
+	return b
+}
+
+func newSlackImage(url string, title string) *slackImageBlock {
+	return &slackImageBlock{
+		Type:     slackTypeImage,
+		ImageURL: url,
+		AltText:  slackTruncate(title, slackMaxFieldLength),
+		Title:    slackPlainText(title, slackMaxFieldLength),
+	}
+}
+
+//newSlackFileImage shows an image uploaded to Slack, see uploadFile
+func newSlackFileImage(fileID string, title string) *slackImageBlock {
+	return &slackImageBlock{
+		Type:      slackTypeImage,
+		SlackFile: &slackFileItem{ID: fileID},
+		AltText:   slackTruncate(title, slackMaxFieldLength),
+		Title:     slackPlainText(title, slackMaxFieldLength),
+	}
+}
+
+//newSlackContext of markdown texts
+func newSlackContext(texts ...string) *slackContextBlock {
+	context := &slackContextBlock{Type: slackTypeContext}
+	for _, text := range texts {
+		if len(context.Elements) < slackMaxContextElements {
+			context.Elements = append(context.Elements, slackMarkdown(text))
+		}
+	}
+	return context
+}
+
+func newSlackDivider() *slackDividerBlock {
+	return &slackDividerBlock{Type: slackTypeDivider}
+}
+
+//newSlackActions block of buttons
+func newSlackActions(buttons ...*slackButtonElement) *slackActionsBlock {
+	return &slackActionsBlock{Type: slackTypeActions, Elements: buttons}
+}
+
+//newSlackLinkButton opens url. actionID is unique in the message
+func newSlackLinkButton(text string, url string, actionID string) *slackButtonElement {
+	return &slackButtonElement{
+		Type:     slackTypeButton,
+		Text:     slackPlainText(text, slackMaxButtonLength),
+		URL:      url,
+		ActionID: actionID,
+	}
+}
+
+//newSlackButton sends a block_actions interaction with actionID when pressed
+func newSlackButton(text string, actionID string) *slackButtonElement {
+	return &slackButtonElement{
+		Type:     slackTypeButton,
+		Text:     slackPlainText(text, slackMaxButtonLength),
+		ActionID: actionID,
+	}
+}
@@ -0,0 +1,113 @@
+package chatapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var updateGolden = flag.Bool("update", false, "Rewrite the golden files of testdata")
+
+//hostileText breaks JSON built by hand, and Slack markup that isn't escaped
+const hostileText = "Say \"hi\" \\ C:\\new\\table\nline\ttab \x00\x07\x1b[31m <script>alert(1)</script> & <@U123> <!channel> |pipe| > *bold* _it_ ~s~ `code` 🎧 \u2028 ünïcödé"
+
+func hostileProduct() *Product {
+	productURL, _ := url.Parse("https://www.amazon.com/dp/B000000001?tag=a%22b&th=1")
+	return &Product{
+		Title:         hostileText,
+		Description:   strings.Repeat(hostileText, 3),
+		ImageURL:      "https://m.media-amazon.com/images/I/1.jpg",
+		Images:        []Image{{URL: "https://m.media-amazon.com/images/I/1.jpg"}, {URL: "https://m.media-amazon.com/images/I/2.jpg"}},
+		Price:         19.99,
+		OriginalPrice: 24.99,
+		Currency:      "USD",
+		Rating:        4.5,
+		RatingsCount:  1234,
+		Brand:         hostileText,
+		Seller:        "Bob's <Shop> & \"Co\"",
+		ShipsFrom:     "Amazon.com",
+		Prime:         true,
+		URL:           productURL,
+		Categories:    []string{"Electronics", hostileText},
+		TopPositiveReview: &Review{
+			Author: hostileText,
+			Title:  hostileText,
+			Body:   hostileText,
+			Rating: 5,
+		},
+		PriceHistory: &PriceStats{Lowest: 17.5, Highest: 24.99, Average30Days: 20, Observations: 3, Since: time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)},
+	}
+}
+
+func TestSlackBlocksGolden(t *testing.T) {
+	minimalURL, _ := url.Parse("https://www.amazon.com/dp/B000000002")
+	minimal := &Product{Title: "Plain product", URL: minimalURL}
+
+	tests := []struct {
+		golden  string
+		payload *slackPayload
+	}{
+		{"slack_product_hostile.golden", slackProductMessage("C1", "U1", hostileProduct(), "report", 0)},
+		{"slack_product_minimal.golden", slackProductMessage("C1", "U1", minimal, "report", 0)},
+		{"slack_list_hostile.golden", slackProductListMessage("C1", hostileText, []*Product{hostileProduct(), minimal})},
+		{"slack_comparison_hostile.golden", slackComparisonMessage("C1", []*Product{hostileProduct(), minimal})},
+	}
+	for _, test := range tests {
+		data, error := json.MarshalIndent(test.payload, "", "  ")
+		if error != nil {
+			t.Fatalf("%s: %s", test.golden, error)
+		}
+		if !json.Valid(data) {
+			t.Errorf("%s: invalid JSON", test.golden)
+		}
+		if bytes.Contains(data, []byte("<script>")) || bytes.Contains(data, []byte("<!channel>")) {
+			t.Errorf("%s: product text isn't escaped", test.golden)
+		}
+
+		path := filepath.Join("testdata", test.golden)
+		if *updateGolden {
+			ioutil.WriteFile(path, data, 0644)
+			continue
+		}
+		expected, error := ioutil.ReadFile(path)
+		if error != nil {
+			t.Fatalf("%s: %s (go test -update to create it)", test.golden, error)
+		}
+		if !bytes.Equal(data, expected) {
+			t.Errorf("%s doesn't match, got:\n%s", test.golden, data)
+		}
+	}
+}
+
+func TestSlackBlocksRoundTrip(t *testing.T) {
+	data, _ := json.Marshal(slackProductMessage("C1", "U1", hostileProduct(), "report", 0))
+	var message struct {
+		Text   string `json:"text"`
+		Blocks []struct {
+			Type string `json:"type"`
+			Text struct {
+				Text string `json:"text"`
+			} `json:"text"`
+		} `json:"blocks"`
+	}
+	if error := json.Unmarshal(data, &message); error != nil {
+		t.Fatal(error)
+	}
+	if message.Text != slackEscape(hostileText) {
+		t.Errorf("Title changed on the way: %q", message.Text)
+	}
+	if !strings.Contains(message.Blocks[0].Text.Text, slackEscape(hostileText)) {
+		t.Errorf("Title missing from the first section: %q", message.Blocks[0].Text.Text)
+	}
+	for _, block := range message.Blocks {
+		if len([]rune(block.Text.Text)) > slackMaxTextLength {
+			t.Errorf("Section text longer than Slack allows: %d", len([]rune(block.Text.Text)))
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package chatapp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+//slackInteraction is what Slack sends when a button of the app's messages is pressed
+//See https://api.slack.com/reference/interaction-payloads/block-actions
+type slackInteraction struct {
+	Type string `json:"type"` //"block_actions" for buttons
+	User struct {
+		ID string `json:"id"`
+	} `json:"user"`
+	Channel struct {
+		ID string `json:"id"`
+	} `json:"channel"`
+	Container struct {
+		MessageTimeStamp string `json:"message_ts"`
+	} `json:"container"`
+	Actions []struct {
+		ActionID string `json:"action_id"`
+	} `json:"actions"`
+}
+
+//handleInteraction handles an interaction payload, posted to the request URL or sent through Socket Mode
+func (s *Slack) handleInteraction(payload []byte) {
+	var interaction slackInteraction
+	if error := json.Unmarshal(payload, &interaction); error != nil {
+		s.handleError(error)
+		return
+	}
+	if interaction.Type != "block_actions" {
+		return
+	}
+	for _, action := range interaction.Actions {
+		if step := galleryStep(action.ActionID); step != 0 {
+			s.onGalleryButton(&interaction, step)
+		}
+	}
+}
+
+//onGalleryButton updates the product message to show the previous or next image
+//Everyone sees the same message, so only the user who posted the link can page through it. Others are told so privately
+func (s *Slack) onGalleryButton(interaction *slackInteraction, step int) {
+	channelID := interaction.Channel.ID
+	userID := interaction.User.ID
+	gallery := s.galleries.turn(interaction.Container.MessageTimeStamp, userID, step)
+	var error error
+	switch {
+	case gallery == nil:
+		error = s.postEphemeral(channelID, userID, galleryExpiredText)
+	case gallery.authorID != userID:
+		error = s.postEphemeral(channelID, userID, fmt.Sprintf(galleryNotOwnerText, gallery.authorID))
+	default:
+		error = s.updateMessage(interaction.Container.MessageTimeStamp, slackProductMessage(channelID, gallery.authorID, gallery.product, s.reportReactionCode, gallery.index))
+	}
+	if error != nil {
+		s.handleError(error)
+	}
+}
+
+//updateMessage replaces the message at timeStamp with payload, using chat.update
+func (s *Slack) updateMessage(timeStamp string, payload *slackPayload) error {
+	data, _ := json.Marshal(struct {
+		*slackPayload
+		TimeStamp string `json:"ts"`
+	}{payload, timeStamp})
+	return s.checkedAPIRequest("chat.update", data)
+}
+
+//postEphemeral shows text to a single user of a channel, with chat.postEphemeral
+func (s *Slack) postEphemeral(channelID string, userID string, text string) error {
+	data, _ := json.Marshal(struct {
+		Channel string `json:"channel"`
+		User    string `json:"user"`
+		Text    string `json:"text"`
+	}{channelID, userID, text})
+	return s.checkedAPIRequest("chat.postEphemeral", data)
+}
@@ -0,0 +1,77 @@
+package chatapp
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestSlackGalleryButtons(t *testing.T) {
+	s := NewSlackSession("xoxb-bot", "-1", WithoutSignatureVerification())
+	calls, close := slackAPIStandIn(s, nil)
+	defer close()
+	s.galleries.add("1.1", hostileProduct(), "U1")
+
+	press := func(userID string, messageTimeStamp string, actionID string) {
+		payload, _ := json.Marshal(map[string]interface{}{
+			"type":      "block_actions",
+			"user":      map[string]string{"id": userID},
+			"channel":   map[string]string{"id": "C1"},
+			"container": map[string]string{"message_ts": messageTimeStamp},
+			"actions":   []map[string]string{{"action_id": actionID}},
+		})
+		request := httptest.NewRequest("POST", "/", strings.NewReader(url.Values{"payload": {string(payload)}}.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		s.ServeHTTP(httptest.NewRecorder(), request)
+	}
+	shownImage := func(call slackAPICall) string {
+		for _, block := range call.Body["blocks"].([]interface{}) {
+			if block := block.(map[string]interface{}); block["type"] == "image" {
+				return block["image_url"].(string)
+			}
+		}
+		return ""
+	}
+
+	tests := []struct {
+		userID    string
+		timeStamp string
+		actionID  string
+		method    string
+		image     string
+	}{
+		{"U1", "1.1", nextImageID, "chat.update", "https://m.media-amazon.com/images/I/2.jpg"},
+		{"U1", "1.1", nextImageID, "chat.update", "https://m.media-amazon.com/images/I/1.jpg"}, //Wraps around
+		{"U1", "1.1", previousImageID, "chat.update", "https://m.media-amazon.com/images/I/2.jpg"},
+		{"U2", "1.1", previousImageID, "chat.postEphemeral", ""}, //Only the author pages through
+		{"U1", "2.2", nextImageID, "chat.postEphemeral", ""},     //Unknown message
+		{"U1", "1.1", "view-product", "", ""},
+	}
+	for _, test := range tests {
+		*calls = nil
+		press(test.userID, test.timeStamp, test.actionID)
+		if len(test.method) == 0 {
+			if len(*calls) != 0 {
+				t.Errorf("%s shouldn't call Slack, got %+v", test.actionID, *calls)
+			}
+			continue
+		}
+		if len(*calls) != 1 || (*calls)[0].Method != test.method {
+			t.Errorf("%s by %s on %s: Expected %s, got %+v", test.actionID, test.userID, test.timeStamp, test.method, *calls)
+			continue
+		}
+		call := (*calls)[0]
+		switch test.method {
+		case "chat.update":
+			if call.Body["ts"] != test.timeStamp || call.Body["channel"] != "C1" || shownImage(call) != test.image {
+				t.Errorf("%s: Expected %s shown in message %s, got %+v", test.actionID, test.image, test.timeStamp, call.Body)
+			}
+		case "chat.postEphemeral":
+			if call.Body["user"] != test.userID || call.Body["channel"] != "C1" {
+				t.Errorf("Expected a message only %s sees, got %+v", test.userID, call.Body)
+			}
+		}
+	}
+}
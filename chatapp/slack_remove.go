@@ -0,0 +1,61 @@
+package chatapp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+//ErrCannotRemove is returned by Remove when Slack doesn't let the bot delete messages
+var ErrCannotRemove = errors.New("[Slack] Not allowed to delete messages")
+
+//WithRemovalToken deletes messages with a user token (xoxp-) of a workspace admin
+//Bot tokens can only delete the bot's own messages, so without it removing a user's message is refused
+func WithRemovalToken(userToken string) SlackOption {
+	return func(s *Slack) {
+		s.removalToken = userToken
+	}
+}
+
+//deleteMessage with chat.delete
+//Once Slack refuses because of the token (missing scope, bot token), it isn't asked again and ErrCannotRemove is returned right away
+//Refusals of a single message (cant_delete_message, no_permission) are returned, and later messages are still tried
+func (s *Slack) deleteMessage(channelID string, timeStamp string) error {
+	if atomic.LoadInt32(&s.cannotRemove) == 1 {
+		return ErrCannotRemove
+	}
+	token := s.removalToken
+	if len(token) == 0 {
+		token = s.token
+	}
+
+	data, _ := json.Marshal(struct {
+		Channel   string `json:"channel"`
+		TimeStamp string `json:"ts"`
+	}{
+		Channel:   channelID,
+		TimeStamp: timeStamp,
+	})
+	resData, error := s.apiRequestWithToken(token, "chat.delete", data)
+	if error != nil {
+		return error
+	}
+
+	var response struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	json.Unmarshal(resData, &response)
+	switch {
+	case response.OK:
+		return nil
+	case response.Error == "missing_scope" || response.Error == "not_allowed_token_type":
+		atomic.StoreInt32(&s.cannotRemove, 1)
+		return fmt.Errorf("%w: %s", ErrCannotRemove, response.Error)
+	case response.Error == "cant_delete_message" || response.Error == "no_permission":
+		return fmt.Errorf("%w: %s", ErrCannotRemove, response.Error)
+	default:
+		return fmt.Errorf("[Slack] chat.delete failed: %s", response.Error)
+	}
+}
@@ -0,0 +1,103 @@
+package chatapp
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type slackAPICall struct {
+	Method        string
+	Authorization string
+	Body          map[string]interface{}
+}
+
+//slackAPIStandIn records Web API calls, and answers them with responses by method
+func slackAPIStandIn(s *Slack, responses map[string]string) (calls *[]slackAPICall, close func()) {
+	calls = &[]slackAPICall{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := slackAPICall{Method: r.URL.Path[1:], Authorization: r.Header.Get("Authorization")}
+		data, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(data, &call.Body)
+		*calls = append(*calls, call)
+		response, found := responses[call.Method]
+		if !found {
+			response = `{"ok":true}`
+		}
+		w.Write([]byte(response))
+	}))
+	s.apiURL = server.URL + "/"
+	return calls, server.Close
+}
+
+func TestSlackRemove(t *testing.T) {
+	s := NewSlackSession("xoxb-bot", "-1", WithRemovalToken("xoxp-admin"))
+	calls, close := slackAPIStandIn(s, nil)
+	defer close()
+
+	actions := &slackMessageActions{event: &slackMessage{ChannelID: "C1", TimeStamp: "1.1"}, slack: s, onlyALink: true}
+	if error := actions.Remove(); error != nil {
+		t.Fatal(error)
+	}
+	if len(*calls) != 1 || (*calls)[0].Method != "chat.delete" || (*calls)[0].Authorization != "Bearer xoxp-admin" || (*calls)[0].Body["ts"] != "1.1" {
+		t.Errorf("Expected chat.delete of 1.1 with the removal token, got %+v", *calls)
+	}
+
+	*calls = nil
+	actions.onlyALink = false
+	actions.Remove()
+	if len(*calls) != 0 {
+		t.Errorf("Messages with more than a link shouldn't be deleted")
+	}
+	actions.onlyALink = true
+
+	refusals := []struct {
+		error string
+		calls int
+	}{
+		{"cant_delete_message", 2}, //This message only
+		{"no_permission", 2},
+		{"missing_scope", 1}, //Every message
+		{"not_allowed_token_type", 1},
+	}
+	for _, refusal := range refusals {
+		s = NewSlackSession("xoxb-bot", "-1")
+		calls, close = slackAPIStandIn(s, map[string]string{"chat.delete": `{"ok":false,"error":"` + refusal.error + `"}`})
+		actions.slack = s
+		for i := 0; i < 2; i++ {
+			if error := actions.Remove(); !errors.Is(error, ErrCannotRemove) {
+				t.Errorf("%s: Expected ErrCannotRemove, got %v", refusal.error, error)
+			}
+		}
+		if len(*calls) != refusal.calls {
+			t.Errorf("%s: Expected %d calls to Slack, got %d", refusal.error, refusal.calls, len(*calls))
+		}
+		close()
+	}
+}
+
+func TestSlackThreadReplies(t *testing.T) {
+	s := NewSlackSession("xoxb-bot", "-1", WithThreadReplies())
+	calls, close := slackAPIStandIn(s, nil)
+	defer close()
+
+	tests := []struct {
+		event  slackMessage
+		thread string
+	}{
+		{slackMessage{ChannelID: "C1", TimeStamp: "1.1"}, "1.1"},
+		{slackMessage{ChannelID: "C1", TimeStamp: "2.2", ThreadTimeStamp: "1.1"}, "1.1"},
+	}
+	for _, test := range tests {
+		*calls = nil
+		actions := &slackMessageActions{event: &test.event, slack: s, onlyALink: true}
+		actions.Remove()
+		actions.RespondWithText("Hi")
+		if len(*calls) != 1 || (*calls)[0].Method != "chat.postMessage" || (*calls)[0].Body["thread_ts"] != test.thread {
+			t.Errorf("Expected only a reply in thread %s, got %+v", test.thread, *calls)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package chatapp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+//slackSignatureMaxAge of a request before it's treated as a replay
+const slackSignatureMaxAge = 5 * time.Minute
+
+//ErrInvalidSlackSignature is returned for requests that weren't signed by Slack with the signing secret
+var ErrInvalidSlackSignature = errors.New("[Slack] Invalid request signature")
+
+//ErrMissingSigningSecret is passed to the error handler when Start would serve requests without verifying them, see WithSigningSecret
+var ErrMissingSigningSecret = errors.New("[Slack] No signing secret, requests can't be verified. Not listening for events")
+
+//now is replaced in tests
+var now = time.Now
+
+//SlackOption configures a Slack session, see NewSlackSession
+type SlackOption func(s *Slack)
+
+//WithSigningSecret rejects requests not signed with the app's signing secret (Basic Information > App Credentials)
+func WithSigningSecret(secret string) SlackOption {
+	return func(s *Slack) {
+		s.signingSecret = secret
+	}
+}
+
+//WithoutSignatureVerification accepts requests without a signing secret, for deployments where only Slack can reach the server
+func WithoutSignatureVerification() SlackOption {
+	return func(s *Slack) {
+		s.allowUnsigned = true
+	}
+}
+
+//verify a request came from Slack. Without a signing secret, requests are only accepted WithoutSignatureVerification
+func (s *Slack) verify(header http.Header, body []byte) error {
+	if len(s.signingSecret) == 0 {
+		if s.allowUnsigned {
+			return nil
+		}
+		return ErrMissingSigningSecret
+	}
+	return verifySlackSignature(s.signingSecret, header, body)
+}
+
+//slackSignature of a request body, as sent by Slack in X-Slack-Signature
+func slackSignature(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+//verifySlackSignature checks that a request was signed by Slack less than slackSignatureMaxAge ago
+//See https://api.slack.com/authentication/verifying-requests-from-slack
+func verifySlackSignature(secret string, header http.Header, body []byte) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, error := strconv.ParseInt(timestamp, 10, 64)
+	if error != nil {
+		return fmt.Errorf("%w: bad timestamp %q", ErrInvalidSlackSignature, timestamp)
+	}
+	age := now().Sub(time.Unix(seconds, 0))
+	if age > slackSignatureMaxAge || age < -slackSignatureMaxAge {
+		return fmt.Errorf("%w: timestamp is %s old", ErrInvalidSlackSignature, age)
+	}
+
+	expected := slackSignature(secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature"))) {
+		return ErrInvalidSlackSignature
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package chatapp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlackSignature(t *testing.T) {
+	const secret = "8f742231b10e8888abcd99yyyzzz85a5"
+	current := time.Unix(1531420618, 0)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	body := `{"type":"url_verification","challenge":"3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P"}`
+	timestamp := strconv.FormatInt(current.Unix(), 10)
+	signature := slackSignature(secret, timestamp, []byte(body))
+
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		body      string
+		status    int
+	}{
+		{"Signed", timestamp, signature, body, http.StatusOK},
+		{"Unsigned", "", "", body, http.StatusUnauthorized},
+		{"Wrong signature", timestamp, slackSignature("other secret", timestamp, []byte(body)), body, http.StatusUnauthorized},
+		{"Tampered body", timestamp, signature, strings.Replace(body, "3e", "4e", 1), http.StatusUnauthorized},
+		{"Too large", timestamp, signature, body + strings.Repeat(" ", maxSlackEventSize), http.StatusBadRequest},
+		{"Replayed", strconv.FormatInt(current.Add(-6*time.Minute).Unix(), 10), slackSignature(secret, strconv.FormatInt(current.Add(-6*time.Minute).Unix(), 10), []byte(body)), body, http.StatusUnauthorized},
+	}
+
+	s := NewSlackSession("", "-1", WithSigningSecret(secret))
+	for _, test := range tests {
+		request := httptest.NewRequest("POST", "/", strings.NewReader(test.body))
+		request.Header.Set("X-Slack-Request-Timestamp", test.timestamp)
+		request.Header.Set("X-Slack-Signature", test.signature)
+		response := httptest.NewRecorder()
+		s.ServeHTTP(response, request)
+
+		if response.Code != test.status {
+			t.Errorf("%s: expected status %d, got %d", test.name, test.status, response.Code)
+		}
+		if test.status == http.StatusUnauthorized && strings.TrimSpace(response.Body.String()) != http.StatusText(http.StatusUnauthorized) {
+			t.Errorf("%s: expected a plain 401, got %s", test.name, response.Body.String())
+		}
+		if test.status == http.StatusOK && response.Body.String() != "3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P" {
+			t.Errorf("%s: expected the challenge back, got %s", test.name, response.Body.String())
+		}
+	}
+
+	//Slack's example from https://api.slack.com/authentication/verifying-requests-from-slack
+	example := "token=xyzz0WbapA4vBCDEFasx0q6G&team_id=T1DC2JH3J&team_domain=testteamnow&channel_id=G8PSS9T3V&channel_name=foobar&user_id=U2CERLKJA&user_name=roadrunner&command=%2Fwebhook-collect&text=&response_url=https%3A%2F%2Fhooks.slack.com%2Fcommands%2FT1DC2JH3J%2F397700885554%2F96rGlfmibIGlgcZRskXaIFfN&trigger_id=398738663015.47445629121.803a0bc887a14d10d2c447fce8b6703c"
+	if signature := slackSignature(secret, "1531420618", []byte(example)); signature != "v0=a2114d57b48eac39b9ad189dd8316235a7b4a8d21a10bd27519666489c69b503" {
+		t.Errorf("Signature doesn't match Slack's example: %s", signature)
+	}
+}
+
+func TestSlackWithoutSigningSecret(t *testing.T) {
+	body := `{"type":"url_verification","challenge":"challenge"}`
+	tests := []struct {
+		name    string
+		options []SlackOption
+		status  int
+	}{
+		{"Refused", nil, http.StatusUnauthorized},
+		{"Allowed", []SlackOption{WithoutSignatureVerification()}, http.StatusOK},
+	}
+	for _, test := range tests {
+		response := httptest.NewRecorder()
+		NewSlackSession("", "-1", test.options...).ServeHTTP(response, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+		if response.Code != test.status {
+			t.Errorf("%s: expected status %d, got %d", test.name, test.status, response.Code)
+		}
+	}
+
+	var startError error
+	started := make(chan struct{})
+	go func() {
+		NewSlackSession("", "-1", WithErrorHandler(func(e error) { startError = e })).Start("127.0.0.1:0")
+		close(started)
+	}()
+	select {
+	case <-started:
+		if startError != ErrMissingSigningSecret {
+			t.Errorf("Expected ErrMissingSigningSecret, got %v", startError)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Started listening without a signing secret")
+	}
+}
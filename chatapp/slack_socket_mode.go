@@ -0,0 +1,144 @@
+package chatapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+//Delays between Socket Mode reconnection attempts, doubled after every failed attempt
+const (
+	socketModeMinRetryDelay = time.Second
+	socketModeMaxRetryDelay = time.Minute
+)
+
+//WithSocketMode receives events through a WebSocket opened with an app-level token (xapp-, with connections:write)
+//instead of an HTTP server, for deployments Slack can't reach. See https://api.slack.com/apis/connections/socket
+func WithSocketMode(appToken string) SlackOption {
+	return func(s *Slack) {
+		s.appToken = appToken
+	}
+}
+
+//socketModeEnvelope wraps everything Slack sends through Socket Mode
+type socketModeEnvelope struct {
+	EnvelopeID string          `json:"envelope_id"` //Acknowledged by sending it back. Empty for "hello" and "disconnect"
+	Type       string          `json:"type"`        //"hello", "events_api", "interactive", "disconnect", ...
+	Reason     string          `json:"reason"`      //Why Slack asks to reconnect, for "disconnect"
+	Payload    json.RawMessage `json:"payload"`     //For "events_api" and "interactive", what would be posted to the request URL
+}
+
+//openSocketModeURL asks Slack for the WebSocket URL of a new connection
+func (s *Slack) openSocketModeURL() (string, error) {
+	resData, error := s.apiRequestWithToken(s.appToken, "apps.connections.open", nil)
+	if error != nil {
+		return "", error
+	}
+	var response struct {
+		OK    bool   `json:"ok"`
+		URL   string `json:"url"`
+		Error string `json:"error"`
+	}
+	json.Unmarshal(resData, &response)
+	if !response.OK {
+		return "", fmt.Errorf("[Slack] apps.connections.open failed: %s", response.Error)
+	}
+	return response.URL, nil
+}
+
+//runSocketMode connects, and reconnects whenever the connection ends, until done is closed
+func (s *Slack) runSocketMode(done <-chan struct{}) {
+	retryDelay := socketModeMinRetryDelay
+	for {
+		connected, error := s.socketModeConnection(done)
+		if error != nil {
+			s.handleError(error)
+		}
+		if connected {
+			retryDelay = socketModeMinRetryDelay
+			continue
+		}
+
+		select {
+		case <-done:
+			return
+		case <-time.After(retryDelay):
+		}
+		if retryDelay *= 2; retryDelay > socketModeMaxRetryDelay {
+			retryDelay = socketModeMaxRetryDelay
+		}
+	}
+}
+
+//socketModeConnection handles the events of one connection until Slack asks to reconnect, the connection fails, or done is closed
+//connected is false when the connection didn't get to Slack's hello
+func (s *Slack) socketModeConnection(done <-chan struct{}) (connected bool, e error) {
+	select {
+	case <-done:
+		return false, nil
+	default:
+	}
+
+	url, error := s.openSocketModeURL()
+	if error != nil {
+		return false, error
+	}
+	connection, _, error := websocket.DefaultDialer.Dial(url, nil)
+	if error != nil {
+		return false, error
+	}
+	defer connection.Close()
+
+	//Closing the connection stops ReadJSON when done is closed
+	finished := make(chan struct{})
+	defer close(finished)
+	go func() {
+		select {
+		case <-done:
+			connection.Close()
+		case <-finished:
+		}
+	}()
+
+	for {
+		var envelope socketModeEnvelope
+		if error := connection.ReadJSON(&envelope); error != nil {
+			select {
+			case <-done:
+				return connected, nil
+			default:
+				return connected, error
+			}
+		}
+
+		//Slack retries envelopes that aren't acknowledged within 3 seconds, so it's done before handling them
+		if len(envelope.EnvelopeID) > 0 {
+			error := connection.WriteJSON(struct {
+				EnvelopeID string `json:"envelope_id"`
+			}{
+				EnvelopeID: envelope.EnvelopeID,
+			})
+			if error != nil {
+				return connected, error
+			}
+		}
+
+		switch envelope.Type {
+		case "hello":
+			connected = true
+		case "disconnect":
+			return true, nil
+		case "events_api":
+			var message slackEventMessageContainer
+			if error := json.Unmarshal(envelope.Payload, &message); error != nil {
+				s.handleError(error)
+				continue
+			}
+			s.dispatch(&message, nil, nil)
+		case "interactive":
+			s.handleInteraction(envelope.Payload)
+		}
+	}
+}
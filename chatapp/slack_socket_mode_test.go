@@ -0,0 +1,93 @@
+package chatapp
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestSlackSocketMode(t *testing.T) {
+	opens := make(chan string, 10)
+	acks := make(chan string, 10)
+	upgrader := websocket.Upgrader{}
+	var connections int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/apps.connections.open":
+			opens <- r.Header.Get("Authorization")
+			number := atomic.AddInt32(&connections, 1)
+			w.Write([]byte(fmt.Sprintf(`{"ok":true,"url":"ws%s/socket?connection=%d"}`, strings.TrimPrefix(server.URL, "http"), number)))
+		case "/socket":
+			connection, error := upgrader.Upgrade(w, r, nil)
+			if error != nil {
+				return
+			}
+			defer connection.Close()
+			connection.WriteMessage(websocket.TextMessage, []byte(`{"type":"hello"}`))
+			if r.URL.Query().Get("connection") != "1" {
+				//Reconnected, keep the connection open until the session stops
+				connection.ReadMessage()
+				return
+			}
+			connection.WriteMessage(websocket.TextMessage, []byte(`{"envelope_id":"e1","type":"events_api","payload":{"team_id":"T1","event":{"type":"message","channel":"C1","user":"U1","text":"<https://www.amazon.com/dp/B000000001>","ts":"1.1","blocks":[{"elements":[{"elements":[{"type":"link","url":"https://www.amazon.com/dp/B000000001"}]}]}]}}}`))
+			var ack struct {
+				EnvelopeID string `json:"envelope_id"`
+			}
+			connection.ReadJSON(&ack)
+			acks <- ack.EnvelopeID
+			connection.WriteMessage(websocket.TextMessage, []byte(`{"type":"disconnect","reason":"refresh_requested"}`))
+			connection.ReadMessage()
+		}
+	}))
+	defer server.Close()
+
+	s := NewSlackSession("xoxb-bot", "-1", WithSocketMode("xapp-app"), WithErrorHandler(func(e error) { t.Log(e) }))
+	s.apiURL = server.URL + "/"
+	messages := make(chan *Message, 1)
+	s.OnMessage(func(_ Session, m *Message) {
+		messages <- m
+	})
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		s.runSocketMode(done)
+		close(stopped)
+	}()
+
+	expect := func(what string, c chan string, expected string) {
+		select {
+		case value := <-c:
+			if value != expected {
+				t.Errorf("Expected %s %q, got %q", what, expected, value)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+	}
+	expect("connection with the app token", opens, "Bearer xapp-app")
+	select {
+	case m := <-messages:
+		if m.Content != "https://www.amazon.com/dp/B000000001" || m.ChannelID != "C1" {
+			t.Errorf("Unexpected message %+v", m)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the message event")
+	}
+	expect("ack", acks, "e1")
+	expect("reconnection after disconnect", opens, "Bearer xapp-app")
+
+	close(done)
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Socket Mode didn't stop")
+	}
+}
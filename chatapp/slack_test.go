@@ -0,0 +1,50 @@
+package chatapp
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSlackUploadPriceChart(t *testing.T) {
+	uploaded := []byte{}
+	var completed map[string]string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/files.getUploadURLExternal":
+			if r.FormValue("filename") != "price-history.png" || r.FormValue("length") != "3" {
+				t.Errorf("Unexpected upload %v", r.Form)
+			}
+			w.Write([]byte(`{"ok":true,"upload_url":"` + server.URL + `/upload","file_id":"F1"}`))
+		case "/upload":
+			uploaded, _ = ioutil.ReadAll(r.Body)
+		case "/files.completeUploadExternal":
+			r.ParseForm()
+			completed = map[string]string{}
+			for key := range r.PostForm {
+				completed[key] = r.PostForm.Get(key)
+			}
+			w.Write([]byte(`{"ok":true}`))
+		default:
+			t.Errorf("Unexpected call to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	s := NewSlackSession("xoxb-bot", "-1")
+	s.apiURL = server.URL + "/"
+	if error := s.uploadPriceChart("C1", "1.1", &Product{Title: "Keyboard", PriceChart: []byte{1, 2, 3}}); error != nil {
+		t.Fatal(error)
+	}
+	if string(uploaded) != "\x01\x02\x03" {
+		t.Errorf("Expected the chart to be uploaded, got %v", uploaded)
+	}
+	var files []map[string]string
+	json.Unmarshal([]byte(completed["files"]), &files)
+	if completed["channel_id"] != "C1" || completed["thread_ts"] != "1.1" || len(files) != 1 || files[0]["id"] != "F1" || files[0]["title"] != "Price history: Keyboard" {
+		t.Errorf("Expected the file to be shared in the thread, got %v", completed)
+	}
+}
@@ -0,0 +1,172 @@
+package chatapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+//SlackMode picks how products are posted in a workspace
+type SlackMode string
+
+//Slack modes
+const (
+	SlackReplyMode  SlackMode = "reply"  //A new bot message for every link (default)
+	SlackUnfurlMode SlackMode = "unfurl" //The product is attached under the user's own message with chat.unfurl. Needs the links:read and links:write scopes, and the store domains as App unfurl domains
+)
+
+//ParseSlackMode returns the SlackMode named by s ("reply", "unfurl")
+func ParseSlackMode(s string) (SlackMode, error) {
+	switch mode := SlackMode(s); mode {
+	case SlackReplyMode, SlackUnfurlMode:
+		return mode, nil
+	}
+	return "", fmt.Errorf("[Slack] Unknown mode %q, expected %q or %q", s, SlackReplyMode, SlackUnfurlMode)
+}
+
+//WithDefaultMode of workspaces without a mode set by WithWorkspaceMode
+func WithDefaultMode(mode SlackMode) SlackOption {
+	return func(s *Slack) {
+		s.defaultMode = mode
+	}
+}
+
+//WithWorkspaceMode sets the mode of a workspace (team ID, "T0123...")
+func WithWorkspaceMode(teamID string, mode SlackMode) SlackOption {
+	return func(s *Slack) {
+		s.workspaceModes[teamID] = mode
+	}
+}
+
+func (s *Slack) modeOf(teamID string) SlackMode {
+	if mode, found := s.workspaceModes[teamID]; found {
+		return mode
+	}
+	if len(s.defaultMode) > 0 {
+		return s.defaultMode
+	}
+	return SlackReplyMode
+}
+
+type slackSharedLink struct {
+	Domain string `json:"domain"`
+	URL    string `json:"url"`
+}
+
+//slackUnfurlActions respond to a link_shared event by unfurling the link
+type slackUnfurlActions struct {
+	event *slackMessage
+	link  string
+	id    string //See unfurlID
+	slack *Slack
+}
+
+//Remove implementation for Actions. The user's message is kept, it's where the product is shown
+func (a *slackUnfurlActions) Remove() error {
+	return nil
+}
+
+//RespondWithProduct implementation for Actions. Unfurls aren't messages of the app, so their gallery can't be paged through
+//The price chart is uploaded privately and shown in the unfurl. Failing that, the product is unfurled without it
+func (a *slackUnfurlActions) RespondWithProduct(p *Product) (string, error) {
+	blocks := slackProductMessage(a.event.ChannelID, a.event.UserID, p, a.slack.reportReactionCode, slackNoGalleryButtons).Blocks
+	if len(p.PriceChart) > 0 {
+		fileID, error := a.slack.uploadFile(priceChartFileName, "Price history: "+p.Title, p.PriceChart, "", "")
+		if error == nil {
+			var id string
+			withChart := append(append([]slackBlock{}, blocks...), newSlackFileImage(fileID, "Price history"))
+			if id, error = a.unfurl(withChart); error == nil {
+				return id, nil
+			}
+		}
+		a.slack.handleError(error)
+	}
+	return a.unfurl(blocks)
+}
+
+//RespondWithText implementation for Actions
+func (a *slackUnfurlActions) RespondWithText(text string) (string, error) {
+	return a.unfurl([]slackBlock{newSlackSection(text)})
+}
+
+//RespondWithProductList implementation for Actions
+func (a *slackUnfurlActions) RespondWithProductList(title string, products []*Product) (string, error) {
+	return a.unfurl(slackProductListMessage(a.event.ChannelID, title, products).Blocks)
+}
+
+//RespondWithComparison implementation for Actions
+func (a *slackUnfurlActions) RespondWithComparison(products []*Product) (string, error) {
+	return a.unfurl(slackComparisonMessage(a.event.ChannelID, products).Blocks)
+}
+
+//unfurl the link with blocks
+//Returns the ID of the unfurl, see unfurlID
+func (a *slackUnfurlActions) unfurl(blocks []slackBlock) (string, error) {
+	type unfurl struct {
+		Blocks []slackBlock `json:"blocks"`
+	}
+	e := a.event
+	data, _ := json.Marshal(struct {
+		Channel   string            `json:"channel,omitempty"`
+		TimeStamp string            `json:"ts,omitempty"`
+		UnfurlID  string            `json:"unfurl_id,omitempty"`
+		Source    string            `json:"source,omitempty"`
+		Unfurls   map[string]unfurl `json:"unfurls"`
+	}{
+		Channel:   e.ChannelID,
+		TimeStamp: e.MessageTimeStamp,
+		UnfurlID:  e.UnfurlID,
+		Source:    e.Source,
+		Unfurls:   map[string]unfurl{a.link: {Blocks: blocks}},
+	})
+
+	resData, error := a.slack.apiRequest("chat.unfurl", data)
+	if error != nil {
+		return "", error
+	}
+	var response struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	json.Unmarshal(resData, &response)
+	if !response.OK {
+		return "", fmt.Errorf("[Slack] chat.unfurl failed: %s", response.Error)
+	}
+	return a.id, nil
+}
+
+//unfurlID identifies the product unfurled for a link of a message
+//Reactions to the user's message are reports / track requests of the product when it has a single link. They can't tell
+//the products of several links apart, so those get an ID per link and reactions to them are ignored
+func unfurlID(messageTimeStamp string, link string, links int) string {
+	if links == 1 {
+		return messageTimeStamp
+	}
+	return messageTimeStamp + " " + link
+}
+
+//onLinkShared calls cb with a message per shared link, for workspaces in unfurl mode
+//Each link is unfurled on its own, so messages with several links aren't answered with a comparison
+func (s *Slack) onLinkShared(cb OnMessageCallback) slackEventHandlerFunc {
+	return func(emc *slackEventMessageContainer, w http.ResponseWriter, r *http.Request) {
+		e := emc.Event
+		if s.modeOf(emc.TeamID) != SlackUnfurlMode {
+			return
+		}
+		for _, link := range e.Links {
+			id := unfurlID(e.MessageTimeStamp, link.URL, len(e.Links))
+			cb(s, &Message{
+				ID:        id,
+				AuthorID:  e.UserID,
+				ChannelID: e.ChannelID,
+				Content:   link.URL,
+				Actions: &slackUnfurlActions{
+					event: &e,
+					link:  link.URL,
+					id:    id,
+					slack: s,
+				},
+			})
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package chatapp
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSlackUnfurlMode(t *testing.T) {
+	s := NewSlackSession("", "-1", WithoutSignatureVerification(), WithWorkspaceMode("TUNFURL", SlackUnfurlMode))
+	messages := []*Message{}
+	s.OnMessage(func(_ Session, m *Message) {
+		messages = append(messages, m)
+	})
+	send := func(body string) {
+		s.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(body)))
+	}
+
+	message := `{"team_id":"%s","event":{"type":"message","channel":"C1","user":"U1","text":"https://amzn.to/x","blocks":[{"elements":[{"elements":[{"type":"link","url":"https://amzn.to/x"}]}]}]}}`
+	linkShared := `{"team_id":"%s","event":{"type":"link_shared","channel":"C1","user":"U1","message_ts":"123.456","links":[{"domain":"amzn.to","url":"https://amzn.to/x"},{"domain":"amazon.com","url":"https://www.amazon.com/dp/B000000001"}]}}`
+
+	send(strings.Replace(message, "%s", "TREPLY", 1))
+	send(strings.Replace(linkShared, "%s", "TREPLY", 1))
+	if len(messages) != 1 {
+		t.Fatalf("Reply mode should only handle message events, got %d messages", len(messages))
+	}
+	if _, ok := messages[0].Actions.(*slackMessageActions); !ok {
+		t.Errorf("Reply mode should reply with new messages")
+	}
+
+	messages = nil
+	send(strings.Replace(message, "%s", "TUNFURL", 1))
+	send(strings.Replace(linkShared, "%s", "TUNFURL", 1))
+	if len(messages) != 2 {
+		t.Fatalf("Unfurl mode should handle each shared link, got %d messages", len(messages))
+	}
+	for index, link := range []string{"https://amzn.to/x", "https://www.amazon.com/dp/B000000001"} {
+		actions, ok := messages[index].Actions.(*slackUnfurlActions)
+		if !ok || actions.link != link || messages[index].Content != link || messages[index].ID != "123.456 "+link || actions.id != messages[index].ID {
+			t.Errorf("Expected an unfurl of %s under message 123.456, got %+v", link, messages[index])
+		}
+	}
+
+	messages = nil
+	send(`{"team_id":"TUNFURL","event":{"type":"link_shared","channel":"C1","user":"U1","message_ts":"123.456","links":[{"domain":"amzn.to","url":"https://amzn.to/x"}]}}`)
+	if len(messages) != 1 || messages[0].ID != "123.456" {
+		t.Errorf("A single link should be unfurled as the message itself, for reactions to it. Got %+v", messages)
+	}
+}
+
+func TestSlackUnfurlPriceChart(t *testing.T) {
+	s := NewSlackSession("xoxb-bot", "-1")
+	responses := map[string]string{}
+	calls, close := slackAPIStandIn(s, responses)
+	defer close()
+	actions := &slackUnfurlActions{event: &slackMessage{ChannelID: "C1", MessageTimeStamp: "1.1"}, link: "https://amzn.to/x", id: "1.1", slack: s}
+	product := &Product{Title: "Keyboard", PriceChart: []byte{1, 2, 3}}
+
+	chartFile := func(call slackAPICall) string {
+		unfurl := call.Body["unfurls"].(map[string]interface{})["https://amzn.to/x"].(map[string]interface{})
+		for _, block := range unfurl["blocks"].([]interface{}) {
+			if file, found := block.(map[string]interface{})["slack_file"]; found {
+				return file.(map[string]interface{})["id"].(string)
+			}
+		}
+		return ""
+	}
+
+	tests := []struct {
+		name      string
+		uploadURL string
+		methods   []string
+		chartFile string
+	}{
+		{"uploaded", `{"ok":true,"upload_url":"` + s.apiURL + `upload","file_id":"F1"}`, []string{"files.getUploadURLExternal", "upload", "files.completeUploadExternal", "chat.unfurl"}, "F1"},
+		{"upload refused", `{"ok":false,"error":"not_allowed"}`, []string{"files.getUploadURLExternal", "chat.unfurl"}, ""},
+	}
+	for _, test := range tests {
+		*calls = nil
+		responses["files.getUploadURLExternal"] = test.uploadURL
+		if _, error := actions.RespondWithProduct(product); error != nil {
+			t.Fatalf("%s: %s", test.name, error)
+		}
+		methods := []string{}
+		for _, call := range *calls {
+			methods = append(methods, call.Method)
+		}
+		if strings.Join(methods, " ") != strings.Join(test.methods, " ") {
+			t.Errorf("%s: Expected calls to %v, got %v", test.name, test.methods, methods)
+			continue
+		}
+		if file := chartFile((*calls)[len(*calls)-1]); file != test.chartFile {
+			t.Errorf("%s: Expected the chart %q in the unfurl, got %q", test.name, test.chartFile, file)
+		}
+	}
+}
+
+func TestParseSlackMode(t *testing.T) {
+	for _, mode := range []SlackMode{SlackReplyMode, SlackUnfurlMode} {
+		if parsed, error := ParseSlackMode(string(mode)); error != nil || parsed != mode {
+			t.Errorf("Expected %s, got %s %v", mode, parsed, error)
+		}
+	}
+	if _, error := ParseSlackMode("unfurled"); error == nil {
+		t.Errorf("Expected an error for an unknown mode")
+	}
+}
@@ -7,7 +7,8 @@ import (
 )
 
 type config struct {
-	HTTPCookies []http.Cookie `json:"HTTPCookies"`
+	HTTPCookies         []http.Cookie     `json:"HTTPCookies"`
+	SlackWorkspaceModes map[string]string `json:"SlackWorkspaceModes"` //Team ID to "reply" or "unfurl", see chatapp.SlackMode
 }
 
 func readConfigFromFile(filePath string) config {
@@ -6,5 +6,6 @@ require github.com/PuerkitoBio/goquery v1.5.1
 
 require (
 	github.com/aws/aws-lambda-go v1.18.0
-	github.com/bwmarrin/discordgo v0.22.0
+	github.com/bwmarrin/discordgo v0.27.1
+	github.com/gorilla/websocket v1.4.2
 )
@@ -1,46 +1,61 @@
 package main
 
 import (
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
-
-	"github.com/programmingparody/amazing-bot/chatapp"
-	"github.com/programmingparody/amazing-bot/scrapers/amazonscraper"
+	"time"
 )
 
-//HTTPFetcher sends a request to Amazon, parses the HTML, and returns a Product
+//HTTPFetcher downloads pages and follows redirects like a browser would. Parsing is left to the scrapers (see masterFetcher)
 type HTTPFetcher struct {
 	Cookies []http.Cookie
 }
 
-//Fetch Product from URL
-func (hf *HTTPFetcher) Fetch(url *url.URL) (*chatapp.Product, error) {
-	html, error := hf.GetHTML(url)
-	if error != nil {
-		return nil, error
-	}
-	amazonProduct, error := amazonscraper.ParseProductHTML(html)
-	if error != nil {
-		return nil, error
-	}
+func (hf *HTTPFetcher) newRequest(url *url.URL) *http.Request {
+	request, _ := http.NewRequest("GET", url.String(), nil)
+	request.Header.Add("user-agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_5) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/80.0.3987.122 Safari/537.36")
 
-	product := amazonToChatAppProduct(url, amazonProduct)
-	return &product, nil
+	for _, c := range hf.Cookies {
+		request.AddCookie(&c)
+	}
+	return request
 }
 
 //GetHTML data from the URL parameter
 func (hf *HTTPFetcher) GetHTML(url *url.URL) ([]byte, error) {
-	startingRequest, _ := http.NewRequest("GET", url.String(), nil)
-	startingRequest.Header.Add("user-agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_5) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/80.0.3987.122 Safari/537.36")
+	response, error := http.DefaultClient.Do(hf.newRequest(url))
 
-	for _, c := range hf.Cookies {
-		startingRequest.AddCookie(&c)
+	if error != nil {
+		return nil, error
 	}
-	response, error := http.DefaultClient.Do(startingRequest)
+	defer response.Body.Close()
+	return ioutil.ReadAll(response.Body)
+}
+
+//noRedirectClient returns redirect responses instead of following them
+var noRedirectClient = &http.Client{
+	Timeout: 10 * time.Second,
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
 
+//NextRedirect returns where the URL redirects to, or nil if the response isn't a redirect
+func (hf *HTTPFetcher) NextRedirect(url *url.URL) (*url.URL, error) {
+	response, error := noRedirectClient.Do(hf.newRequest(url))
 	if error != nil {
 		return nil, error
 	}
-	return ioutil.ReadAll(response.Body)
+	defer response.Body.Close()
+
+	if response.StatusCode < 300 || response.StatusCode >= 400 {
+		return nil, nil
+	}
+	location, error := response.Location()
+	if error != nil {
+		return nil, fmt.Errorf("[HTTPFetcher] Redirect without a location: %s", url)
+	}
+	return location, nil
 }
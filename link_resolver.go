@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+)
+
+//maxResolvedLinks remembered by a linkResolver. The oldest are forgotten first
+const maxResolvedLinks = 1000
+
+//linkResolver follows the redirects of short links (amzn.to, a.co, ...) and remembers where they lead
+type linkResolver struct {
+	fetcher   HTTPFetcher
+	maxHops   int
+	cacheSize int
+	mutex     sync.Mutex
+	cache     map[string]*url.URL
+	order     []string //Keys of cache, oldest first
+}
+
+func newLinkResolver(fetcher HTTPFetcher, maxHops int) *linkResolver {
+	return &linkResolver{
+		fetcher:   fetcher,
+		maxHops:   maxHops,
+		cacheSize: maxResolvedLinks,
+		cache:     make(map[string]*url.URL),
+	}
+}
+
+//Resolve follows redirects, at most maxHops times, until isResolved returns true for the URL
+//Redirects to URLs canFollow returns false for are an error. Links without a scheme ("amzn.to/abc") are resolved as https
+func (r *linkResolver) Resolve(link *url.URL, isResolved func(*url.URL) bool, canFollow func(*url.URL) bool) (*url.URL, error) {
+	if len(link.Scheme) == 0 {
+		withScheme, error := url.Parse("https://" + link.String())
+		if error != nil {
+			return nil, error
+		}
+		link = withScheme
+	}
+	key := link.String()
+
+	r.mutex.Lock()
+	cached := r.cache[key]
+	r.mutex.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	current := link
+	for hops := 0; !isResolved(current); hops++ {
+		if hops >= r.maxHops {
+			return nil, fmt.Errorf("[LinkResolver] Too many redirects (%d): %s", r.maxHops, link)
+		}
+		next, error := r.fetcher.NextRedirect(current)
+		if error != nil {
+			return nil, error
+		}
+		if next == nil {
+			return nil, fmt.Errorf("[LinkResolver] Redirects ended before a product: %s", current)
+		}
+		current = current.ResolveReference(next)
+		if !canFollow(current) {
+			return nil, fmt.Errorf("[LinkResolver] Redirected outside of the store: %s", current)
+		}
+	}
+
+	r.remember(key, current)
+	return current, nil
+}
+
+func (r *linkResolver) remember(key string, resolved *url.URL) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, found := r.cache[key]; !found {
+		r.order = append(r.order, key)
+	}
+	r.cache[key] = resolved
+	for len(r.order) > r.cacheSize {
+		delete(r.cache, r.order[0])
+		r.order = r.order[1:]
+	}
+}
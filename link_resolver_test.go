@@ -0,0 +1,74 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestLinkResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/short":
+			http.Redirect(w, r, "/hop", http.StatusMovedPermanently)
+		case "/hop":
+			http.Redirect(w, r, "/dp/B000000001?ref=short", http.StatusFound)
+		case "/away":
+			http.Redirect(w, r, "https://example.com/dp/B000000001", http.StatusFound)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		}
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+
+	isProduct := func(u *url.URL) bool {
+		return strings.HasPrefix(u.Path, "/dp/")
+	}
+	onServer := func(u *url.URL) bool {
+		return u.Host == serverURL.Host
+	}
+	resolver := newLinkResolver(HTTPFetcher{}, 3)
+
+	link, _ := url.Parse(server.URL + "/short")
+	resolved, error := resolver.Resolve(link, isProduct, onServer)
+	if error != nil {
+		t.Fatal(error)
+	}
+	if expected := server.URL + "/dp/B000000001?ref=short"; resolved.String() != expected {
+		t.Errorf("Expected %s, got %s", expected, resolved)
+	}
+	server.Close()
+	if cached, error := resolver.Resolve(link, isProduct, onServer); error != nil || cached.String() != resolved.String() {
+		t.Errorf("Expected the cached %s, got %v %v", resolved, cached, error)
+	}
+
+	server = httptest.NewServer(server.Config.Handler)
+	defer server.Close()
+	serverURL, _ = url.Parse(server.URL)
+	for _, path := range []string{"/away", "/loop"} {
+		link, _ := url.Parse(server.URL + path)
+		if resolved, error := resolver.Resolve(link, isProduct, onServer); error == nil {
+			t.Errorf("%s: expected an error, got %s", path, resolved)
+		}
+	}
+
+	schemeless, _ := url.Parse("amzn.to/dp/abc")
+	if resolved, _ := resolver.Resolve(schemeless, isProduct, onServer); resolved == nil || resolved.String() != "https://amzn.to/dp/abc" {
+		t.Errorf("Links without a scheme should be resolved as https, got %v", resolved)
+	}
+}
+
+func TestLinkResolverCacheSize(t *testing.T) {
+	resolver := newLinkResolver(HTTPFetcher{}, 3)
+	resolver.cacheSize = 2
+	for _, link := range []string{"https://a.co/1", "https://a.co/2", "https://a.co/3"} {
+		u, _ := url.Parse(link)
+		resolver.remember(link, u)
+	}
+	if len(resolver.cache) != 2 || resolver.cache["https://a.co/1"] != nil {
+		t.Errorf("Expected the oldest link to be forgotten, got %v", resolver.cache)
+	}
+}
@@ -8,10 +8,13 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/programmingparody/amazing-bot/chatapp"
+	"github.com/programmingparody/amazing-bot/scrapers"
+	"github.com/programmingparody/amazing-bot/scrapers/amazonscraper"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -20,11 +23,25 @@ import (
 
 var discordBotToken string
 var slackBotToken string
+var slackSigningSecret string
+var slackAllowUnsigned bool
+var slackMode string
+var slackThreadReplies bool
+var slackRemovalToken string
+var slackAppToken string
 var amazonReferralTag string
 var devMode bool
 var reportDataPath string
 var htmlStoragePath string
 var slackWebPort string
+var selectorsPath string
+var selectorsReloadInterval time.Duration
+var fetchOffers bool
+var priceHistoryPath string
+var watchlistsPath string
+var trackInterval time.Duration
+var trackMaxPerUser int
+var disableComparison bool
 
 func main() {
 	config := readConfigFromFile("./config.json")
@@ -32,11 +49,31 @@ func main() {
 
 	discordBotToken = os.Getenv("DISCORD_BOT_TOKEN")
 	slackBotToken = os.Getenv("SLACK_BOT_TOKEN")
+	slackSigningSecret = os.Getenv("SLACK_SIGNING_SECRET")
+	slackAllowUnsigned = os.Getenv("SLACK_ALLOW_UNSIGNED") == "TRUE"
+	slackMode = os.Getenv("SLACK_MODE")
+	slackThreadReplies = os.Getenv("SLACK_THREAD_REPLIES") == "TRUE"
+	slackRemovalToken = os.Getenv("SLACK_REMOVAL_TOKEN")
+	slackAppToken = os.Getenv("SLACK_APP_TOKEN")
 	amazonReferralTag = os.Getenv("AMZN_REFERRAL_TAG")
 	devMode = os.Getenv("DEV") == "TRUE"
 	reportDataPath = os.Getenv("REPORT_PATH")
 	htmlStoragePath = os.Getenv("HTML_STORAGE_PATH")
 	slackWebPort = os.Getenv("SLACK_WEB_PORT")
+	selectorsPath = os.Getenv("SELECTORS_PATH")
+	selectorsReloadInterval, _ = time.ParseDuration(os.Getenv("SELECTORS_RELOAD_INTERVAL"))
+	fetchOffers = os.Getenv("FETCH_OFFERS") == "TRUE"
+	priceHistoryPath = os.Getenv("PRICE_HISTORY_PATH")
+	watchlistsPath = os.Getenv("WATCHLISTS_PATH")
+	trackInterval, _ = time.ParseDuration(os.Getenv("TRACK_INTERVAL"))
+	if trackInterval <= 0 {
+		trackInterval = time.Hour
+	}
+	trackMaxPerUser, _ = strconv.Atoi(os.Getenv("TRACK_MAX_PER_USER"))
+	if trackMaxPerUser <= 0 {
+		trackMaxPerUser = 10
+	}
+	disableComparison = os.Getenv("DISABLE_COMPARISON") == "TRUE"
 
 	fmt.Printf(`
 	========================
@@ -47,6 +84,9 @@ func main() {
 	Dev Mode:%v
 	HTML Storage Path:%v
 	Reported Products Path: %v
+	Selectors Path: %v
+	Price History Path: %v
+	Watchlists Path: %v
 	========================
 
 	`,
@@ -54,7 +94,23 @@ func main() {
 		amazonReferralTag,
 		devMode,
 		htmlStoragePath,
-		reportDataPath)
+		reportDataPath,
+		selectorsPath,
+		priceHistoryPath,
+		watchlistsPath)
+
+	//Amazon parser selectors (compiled in defaults are used without a file)
+
+	if len(selectorsPath) > 0 {
+		if selectorsReloadInterval > 0 {
+			stopWatchingSelectors := amazonscraper.WatchSelectorsFile(selectorsPath, selectorsReloadInterval, logError)
+			defer stopWatchingSelectors()
+		} else if selectors, error := amazonscraper.LoadSelectorsFromFile(selectorsPath); error != nil {
+			logError(error)
+		} else {
+			amazonscraper.SetSelectors(selectors)
+		}
+	}
 
 	//Bot session setup
 
@@ -64,26 +120,92 @@ func main() {
 	}
 	defer discordSession.Close()
 
-	slackBot := chatapp.NewSlackSession(slackBotToken, "-1")
+	slackOptions := []chatapp.SlackOption{chatapp.WithErrorHandler(logError)}
+	if len(slackAppToken) > 0 {
+		slackOptions = append(slackOptions, chatapp.WithSocketMode(slackAppToken))
+	} else if len(slackSigningSecret) > 0 {
+		slackOptions = append(slackOptions, chatapp.WithSigningSecret(slackSigningSecret))
+	} else if slackAllowUnsigned {
+		fmt.Println("SLACK_ALLOW_UNSIGNED is set, Slack requests won't be verified")
+		slackOptions = append(slackOptions, chatapp.WithoutSignatureVerification())
+	} else {
+		fmt.Println("SLACK_SIGNING_SECRET is not set, Slack events won't be received. Set SLACK_ALLOW_UNSIGNED=\"TRUE\" to accept unverified requests")
+	}
+	if len(slackMode) > 0 {
+		mode, error := chatapp.ParseSlackMode(slackMode)
+		if error != nil {
+			panic(error)
+		}
+		slackOptions = append(slackOptions, chatapp.WithDefaultMode(mode))
+	}
+	if slackThreadReplies {
+		slackOptions = append(slackOptions, chatapp.WithThreadReplies())
+	}
+	if len(slackRemovalToken) > 0 {
+		slackOptions = append(slackOptions, chatapp.WithRemovalToken(slackRemovalToken))
+	}
+	for teamID, name := range config.SlackWorkspaceModes {
+		mode, error := chatapp.ParseSlackMode(name)
+		if error != nil {
+			panic(fmt.Errorf("SlackWorkspaceModes[%s]: %w", teamID, error))
+		}
+		slackOptions = append(slackOptions, chatapp.WithWorkspaceMode(teamID, mode))
+	}
+	slackBot := chatapp.NewSlackSession(slackBotToken, "-1", slackOptions...)
 	go slackBot.Start(slackWebPort)
 
 	//Amazing Bot setup
 
+	//New retailers are added here, next to amazonscraper
+	scraperRegistry := scrapers.NewRegistry(
+		amazonscraper.Scraper{},
+	)
+
+	httpFetcher := HTTPFetcher{Cookies: config.HTTPCookies}
 	masterFetcher := masterFetcher{
-		Fetcher:              HTTPFetcher{Cookies: config.HTTPCookies},
+		Fetcher:              httpFetcher,
+		Scrapers:             scraperRegistry,
+		LinkResolver:         newLinkResolver(httpFetcher, 5),
 		ProductStorage:       newCacheRepo(time.Second * 5),
 		MessageIDProductRepo: newCacheRepo(time.Second * 5),
 		HTMLStorage:          &fileStorage{Extension: "html"},
 		ReportHandler:        onReport,
 		ErrorHandler:         logError,
+		FetchOffers:          fetchOffers,
 		ProductModifier: func(p *chatapp.Product) {
 			applyPromoCode(p.URL, amazonReferralTag)
+			for _, v := range p.Variants {
+				applyPromoCode(v.URL, amazonReferralTag)
+			}
 		},
 	}
+	if len(priceHistoryPath) > 0 {
+		masterFetcher.PriceHistory = &filePriceHistory{Directory: priceHistoryPath}
+	}
 	amazingBot := AmazingBot{
 		Fetcher:            &masterFetcher,
+		Searcher:           &masterFetcher,
+		SearchResultsCount: 5,
+		Scrapers:           scraperRegistry,
 		ProductSentHandler: masterFetcher.createProductSentHandler(),
 		ReportHandler:      masterFetcher.createReportHandler(),
+		SentProducts:       masterFetcher.MessageIDProductRepo,
+		DisableComparison:  disableComparison,
+	}
+	if len(watchlistsPath) > 0 {
+		amazingBot.Tracker = &tracker{
+			Fetcher:      &masterFetcher,
+			Scrapers:     scraperRegistry,
+			Interval:     trackInterval,
+			MaxPerUser:   trackMaxPerUser,
+			Path:         watchlistsPath,
+			ErrorHandler: logError,
+		}
+		if error := amazingBot.Tracker.load(); error != nil {
+			logError(error)
+		}
+		stopTracking := amazingBot.Tracker.Start()
+		defer stopTracking()
 	}
 	amazingBot.Hook(chatapp.NewDiscordSession(discordSession))
 	amazingBot.Hook(slackBot)
@@ -118,8 +240,9 @@ func (s *fileStorage) Get(id string) ([]byte, error) {
 
 func onReport(product *chatapp.Product, html []byte) {
 	testData := ProductTestData{
-		Product: *product,
-		HTML:    string(html),
+		Product:         *product,
+		HTML:            string(html),
+		SelectorVersion: product.SelectorVersion,
 	}
 
 	jsonData, error := json.MarshalIndent(testData, "", "\t")
@@ -1,11 +1,12 @@
 package main
 
 import (
-	"fmt"
 	"net/url"
+	"time"
 
+	"github.com/programmingparody/amazing-bot/chart"
 	"github.com/programmingparody/amazing-bot/chatapp"
-	"github.com/programmingparody/amazing-bot/scrapers/amazonscraper"
+	"github.com/programmingparody/amazing-bot/scrapers"
 )
 
 type byteStorage interface {
@@ -20,12 +21,17 @@ Logs http responses to build test cases when reported
 */
 type masterFetcher struct {
 	Fetcher              HTTPFetcher                                 //Results of Fetcher are put in ProductStorage
+	Scrapers             *scrapers.Registry                          //Picks the scraper that parses the HTML for a URL
+	LinkResolver         *linkResolver                               //Follows short links (amzn.to, a.co, ...) to the product URL
 	ProductStorage       ProductRepo                                 //Checked first before using Fetcher
 	ReportHandler        func(product *chatapp.Product, html []byte) //Called when a product is reported
 	MessageIDProductRepo ProductRepo                                 //Keeps track of products we've respond incase it's reported
 	HTMLStorage          byteStorage                                 //Keeps track of HTTP body responses for logging when reported
 	ProductModifier      func(*chatapp.Product)                      //Called before sending a product. Useful for adding a referral code
 	ErrorHandler         func(error)
+	SearchMarketplace    string            //Regional store searched by Search (For Amazon, the TLD). Empty uses the scraper's default
+	PriceHistory         PriceHistoryStore //Records the price of every product fetched. Optional
+	FetchOffers          bool              //Also fetch the offer listing (one more request per product) of scrapers implementing scrapers.OfferScraper
 }
 
 func (m *masterFetcher) createProductSentHandler() func(e *SentProductEvent) {
@@ -44,7 +50,12 @@ func (m *masterFetcher) createReportHandler() func(s chatapp.Session, messageID
 			}
 		}
 
-		id := urlToID(productSent.URL)
+		_, key, error := m.identify(productSent.URL)
+		if error != nil {
+			m.ErrorHandler(error)
+			return
+		}
+		id := key.String()
 		html, _ := m.HTMLStorage.Get(id)
 		product, _ := m.ProductStorage.Get(id)
 
@@ -53,7 +64,13 @@ func (m *masterFetcher) createReportHandler() func(s chatapp.Session, messageID
 }
 
 func (m *masterFetcher) Fetch(url *url.URL) (*chatapp.Product, error) {
-	id := urlToID(url)
+	scraper, key, error := m.identify(url)
+	if error != nil {
+		m.ErrorHandler(error)
+		return nil, error
+	}
+	id := key.String()
+	url = scraper.CanonicalURL(key)
 
 	storedProduct, error := m.ProductStorage.Get(id)
 	if storedProduct != nil && error == nil {
@@ -71,11 +88,24 @@ func (m *masterFetcher) Fetch(url *url.URL) (*chatapp.Product, error) {
 
 	go m.HTMLStorage.Save(id, html)
 
-	amazonProduct, error := amazonscraper.ParseProductHTML(html)
+	scrapedProduct, error := scraper.ParseProductHTML(url, html)
+	if error != nil {
+		m.ErrorHandler(error)
+		return nil, error
+	}
+	product, error := scraper.ToChatAppProduct(url, scrapedProduct)
 	if error != nil {
 		m.ErrorHandler(error)
+		return nil, error
+	}
+
+	if offerScraper, ok := scraper.(scrapers.OfferScraper); ok && m.FetchOffers {
+		product.Offers = m.fetchOffers(offerScraper, key)
+	}
+
+	if m.PriceHistory != nil {
+		m.recordPrice(key, &product)
 	}
-	product := amazonToChatAppProduct(url, amazonProduct)
 
 	if m.ProductModifier != nil {
 		m.ProductModifier(&product)
@@ -83,9 +113,112 @@ func (m *masterFetcher) Fetch(url *url.URL) (*chatapp.Product, error) {
 
 	m.ProductStorage.Save(id, &product)
 
-	return &product, error
+	return &product, nil
 }
 
-func urlToID(url *url.URL) string {
-	return fmt.Sprintf("%v://%v%v", url.Scheme, url.Host, url.Path)
+//fetchOffers of a product. Failing to get them doesn't fail the product, it's shown without them
+func (m *masterFetcher) fetchOffers(scraper scrapers.OfferScraper, key scrapers.ProductKey) []chatapp.Offer {
+	url := scraper.OfferListingURL(key)
+	html, error := m.Fetcher.GetHTML(url)
+	if error != nil {
+		m.ErrorHandler(error)
+		return nil
+	}
+	offers, error := scraper.ParseOffersHTML(url, html)
+	if error != nil {
+		m.ErrorHandler(error)
+		return nil
+	}
+	return offers
+}
+
+//recordPrice of a freshly fetched product, and show the product's price history: its stats, and a chart once there's enough prices
+func (m *masterFetcher) recordPrice(key scrapers.ProductKey, product *chatapp.Product) {
+	now := time.Now()
+	error := m.PriceHistory.Record(key, PriceObservation{
+		Time:          now,
+		Price:         product.Price,
+		OriginalPrice: product.OriginalPrice,
+		OutOfStock:    product.OutOfStock,
+	})
+	if error != nil {
+		m.ErrorHandler(error)
+	}
+	history, error := m.PriceHistory.History(key)
+	if error != nil {
+		m.ErrorHandler(error)
+		return
+	}
+	product.PriceHistory = priceStats(history, now)
+
+	chartPNG, error := renderPriceChart(history, product.Currency)
+	if error != nil && error != chart.ErrNotEnoughPoints {
+		m.ErrorHandler(error)
+	}
+	product.PriceChart = chartPNG
+}
+
+//Search implements ProductSearcher with the first registered scraper that supports searching
+func (m *masterFetcher) Search(keywords string, limit int) ([]*chatapp.Product, error) {
+	searcher, error := m.Scrapers.Searcher()
+	if error != nil {
+		return nil, error
+	}
+
+	url := searcher.SearchURL(m.SearchMarketplace, keywords)
+	html, error := m.Fetcher.GetHTML(url)
+	if error != nil {
+		m.ErrorHandler(error)
+		return nil, error
+	}
+
+	results, error := searcher.ParseSearchHTML(url, html)
+	if error != nil {
+		m.ErrorHandler(error)
+		return nil, error
+	}
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	products := []*chatapp.Product{}
+	for i := range results {
+		product := &results[i]
+		if m.ProductModifier != nil {
+			m.ProductModifier(product)
+		}
+		products = append(products, product)
+	}
+	return products, nil
+}
+
+//identify the product a URL points to, along with the scraper that handles it
+//Every form of link to the same product shares a key (marketplace, product ID)
+//Short links are resolved first, and the scraper is picked again for the URL they lead to
+//Only redirects to the scraper's short links or store are followed
+func (m *masterFetcher) identify(link *url.URL) (scrapers.Scraper, scrapers.ProductKey, error) {
+	scraper, error := m.Scrapers.ScraperFor(link)
+	if error != nil {
+		return nil, scrapers.ProductKey{}, error
+	}
+	if scraper.IsShortLink(link) && m.LinkResolver != nil {
+		link, error = m.LinkResolver.Resolve(link, func(u *url.URL) bool {
+			resolvedScraper, error := m.Scrapers.ScraperFor(u)
+			if error != nil || resolvedScraper.IsShortLink(u) {
+				return false
+			}
+			_, error = resolvedScraper.ProductKey(u)
+			return error == nil
+		}, func(u *url.URL) bool {
+			return scraper.IsShortLink(u) || scraper.IsStoreURL(u)
+		})
+		if error != nil {
+			return nil, scrapers.ProductKey{}, error
+		}
+		if scraper, error = m.Scrapers.ScraperFor(link); error != nil {
+			return nil, scrapers.ProductKey{}, error
+		}
+	}
+	key, error := scraper.ProductKey(link)
+	return scraper, key, error
 }
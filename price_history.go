@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/programmingparody/amazing-bot/chart"
+	"github.com/programmingparody/amazing-bot/chatapp"
+	"github.com/programmingparody/amazing-bot/scrapers"
+)
+
+//PriceObservation is the price and stock state of a product when it was fetched
+type PriceObservation struct {
+	Time          time.Time `json:"time"`
+	Price         float32   `json:"price"`
+	OriginalPrice float32   `json:"originalPrice"`
+	OutOfStock    bool      `json:"outOfStock"`
+}
+
+//PriceHistoryStore records every observed price of products, oldest first
+type PriceHistoryStore interface {
+	Record(key scrapers.ProductKey, o PriceObservation) error
+	History(key scrapers.ProductKey) ([]PriceObservation, error)
+}
+
+//filePriceHistory is a PriceHistoryStore keeping one JSON file per product in Directory
+type filePriceHistory struct {
+	Directory string
+	mutex     sync.Mutex
+}
+
+func (h *filePriceHistory) fileName(key scrapers.ProductKey) string {
+	return filepath.Join(h.Directory, fmt.Sprintf("%s_%s.json", url.PathEscape(key.Marketplace), url.PathEscape(key.ID)))
+}
+
+//Record implements PriceHistoryStore
+func (h *filePriceHistory) Record(key scrapers.ProductKey, o PriceObservation) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	history, error := h.read(key)
+	if error != nil {
+		return error
+	}
+	data, error := json.Marshal(append(history, o))
+	if error != nil {
+		return error
+	}
+	return ioutil.WriteFile(h.fileName(key), data, 0644)
+}
+
+//History implements PriceHistoryStore
+func (h *filePriceHistory) History(key scrapers.ProductKey) ([]PriceObservation, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.read(key)
+}
+
+func (h *filePriceHistory) read(key scrapers.ProductKey) ([]PriceObservation, error) {
+	history := []PriceObservation{}
+	data, error := ioutil.ReadFile(h.fileName(key))
+	if os.IsNotExist(error) {
+		return history, nil
+	}
+	if error != nil {
+		return nil, error
+	}
+	return history, json.Unmarshal(data, &history)
+}
+
+//priceStats summarises the observations with a price. Averages are over the 30 days before now
+func priceStats(history []PriceObservation, now time.Time) *chatapp.PriceStats {
+	stats := &chatapp.PriceStats{}
+	var sum float32
+	var count int
+	for _, o := range history {
+		if o.Price <= 0 {
+			continue
+		}
+		if stats.Observations == 0 || o.Price < stats.Lowest {
+			stats.Lowest = o.Price
+		}
+		if o.Price > stats.Highest {
+			stats.Highest = o.Price
+		}
+		if stats.Observations == 0 {
+			stats.Since = o.Time
+		}
+		stats.Observations++
+		if now.Sub(o.Time) <= 30*24*time.Hour {
+			sum += o.Price
+			count++
+		}
+	}
+	if stats.Observations == 0 {
+		return nil
+	}
+	if count > 0 {
+		stats.Average30Days = sum / float32(count)
+	}
+	return stats
+}
+
+//renderPriceChart draws the history as a PNG line chart. Returns chart.ErrNotEnoughPoints until 2 prices were seen
+func renderPriceChart(history []PriceObservation, currency string) ([]byte, error) {
+	points := []chart.Point{}
+	for _, o := range history {
+		point := chart.Point{Time: o.Time, Price: o.Price, OriginalPrice: o.OriginalPrice}
+		if o.OutOfStock {
+			point.Price = 0
+		}
+		points = append(points, point)
+	}
+	return chart.Render(points, func(price float32) string {
+		return chatapp.FormatPrice(price, currency)
+	})
+}
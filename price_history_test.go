@@ -0,0 +1,52 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/programmingparody/amazing-bot/chatapp"
+	"github.com/programmingparody/amazing-bot/scrapers"
+)
+
+func TestFilePriceHistory(t *testing.T) {
+	directory, error := ioutil.TempDir("", "price_history")
+	if error != nil {
+		t.Fatal(error)
+	}
+	defer os.RemoveAll(directory)
+
+	now := time.Date(2020, 12, 1, 0, 0, 0, 0, time.UTC)
+	key := scrapers.ProductKey{Marketplace: "com", ID: "B07MPCSHQD"}
+	observations := []PriceObservation{
+		{Time: now.AddDate(0, -2, 0), Price: 10},
+		{Time: now.AddDate(0, 0, -20), Price: 30, OriginalPrice: 40},
+		{Time: now.AddDate(0, 0, -10), OutOfStock: true},
+		{Time: now, Price: 20},
+	}
+
+	history := &filePriceHistory{Directory: directory}
+	for _, o := range observations {
+		if error := history.Record(key, o); error != nil {
+			t.Fatal(error)
+		}
+	}
+	if other, _ := history.History(scrapers.ProductKey{Marketplace: "de", ID: key.ID}); len(other) != 0 {
+		t.Errorf("Expected no history for another marketplace. Result: %v", other)
+	}
+
+	recorded, error := history.History(key)
+	if error != nil {
+		t.Fatal(error)
+	}
+	if !reflect.DeepEqual(recorded, observations) {
+		t.Errorf("Expected: %v Result: %v", observations, recorded)
+	}
+
+	expected := &chatapp.PriceStats{Lowest: 10, Highest: 30, Average30Days: 25, Observations: 3, Since: observations[0].Time}
+	if stats := priceStats(recorded, now); !reflect.DeepEqual(stats, expected) {
+		t.Errorf("Expected: %+v Result: %+v", expected, stats)
+	}
+}
@@ -10,3 +10,8 @@ import (
 type ProductFetcher interface {
 	Fetch(url *url.URL) (*chatapp.Product, error)
 }
+
+//ProductSearcher represents a type that can search products by keywords
+type ProductSearcher interface {
+	Search(keywords string, limit int) ([]*chatapp.Product, error)
+}
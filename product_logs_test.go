@@ -43,10 +43,16 @@ func testFile(file string, t *testing.T) {
 	json.Unmarshal(fileData, &testData)
 
 	parsedAmazonProduct, error := amazonscraper.ParseProductHTML([]byte(testData.HTML))
-	parsedProduct := amazonToChatAppProduct(testData.Product.URL, parsedAmazonProduct)
 	if error != nil {
 		t.Error(error)
+		return
 	}
+	parsedProduct := amazonscraper.ToChatAppProduct(testData.Product.URL, parsedAmazonProduct)
+	if parsedProduct.SelectorVersion != testData.SelectorVersion {
+		t.Logf("Reported with selectors %q, parsed with %q", testData.SelectorVersion, parsedProduct.SelectorVersion)
+	}
+	//Not part of the fixture's Product
+	parsedProduct.SelectorVersion = ""
 	if !reflect.DeepEqual(testData.Product, parsedProduct) {
 		t.Errorf("Not equal:\nv==============Expected==============v\n%v\n\n\nv==============Result==============v\n%v", testData.Product, parsedProduct)
 	}
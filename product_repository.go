@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/programmingparody/amazing-bot/chatapp"
@@ -19,6 +20,7 @@ type cacheItem struct {
 }
 type cacheRepo struct {
 	duration time.Duration
+	mutex    sync.Mutex
 	storage  map[string]*cacheItem
 }
 
@@ -30,6 +32,8 @@ func newCacheRepo(itemDuration time.Duration) *cacheRepo {
 }
 
 func (r *cacheRepo) Save(id string, p *chatapp.Product) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 	r.storage[id] = &cacheItem{
 		product: p,
 		ts:      time.Now(),
@@ -38,6 +42,8 @@ func (r *cacheRepo) Save(id string, p *chatapp.Product) error {
 }
 
 func (r *cacheRepo) Get(id string) (*chatapp.Product, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 	item := r.storage[id]
 	if item == nil {
 		return nil, fmt.Errorf("[CacheRepo] ID not found: %s", id)
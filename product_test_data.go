@@ -6,6 +6,7 @@ import (
 
 //ProductTestData is used in product_logs_test.go to quickly fix parsing issues
 type ProductTestData struct {
-	Product chatapp.Product
-	HTML    string
+	Product         chatapp.Product
+	HTML            string
+	SelectorVersion string //Version of the scraper's selectors when the product was reported
 }
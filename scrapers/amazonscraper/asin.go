@@ -0,0 +1,66 @@
+package amazonscraper
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+//DefaultMarketplace is used when a marketplace can't be found (amazon.com)
+const DefaultMarketplace = "com"
+
+var asinPathRegex = regexp.MustCompile(`(?i)/(?:dp|gp/product|gp/aw/d|dp/product|exec/obidos/asin|o/asin)/([A-Z0-9]{10})(?:[/?#]|$)`)
+var marketplaceHostRegex = regexp.MustCompile(`(?i)(?:^|\.)amazon\.([a-z.]+)$`)
+
+//ExtractASIN from any form of Amazon product URL (/dp/, /gp/product/, slug prefixed, ...)
+//marketplace is the TLD of the Amazon store the link points to, such as "com", "ca" or "co.uk"
+func ExtractASIN(link string) (marketplace string, asin string, found bool) {
+	if !strings.Contains(link, "://") {
+		link = "https://" + link
+	}
+	URL, error := url.Parse(link)
+	if error != nil {
+		return "", "", false
+	}
+
+	marketplace, found = hostMarketplace(URL.Hostname())
+	if !found {
+		return "", "", false
+	}
+	pathMatch := asinPathRegex.FindStringSubmatch(URL.EscapedPath())
+	if pathMatch == nil {
+		return "", "", false
+	}
+
+	return marketplace, strings.ToUpper(pathMatch[1]), true
+}
+
+//hostMarketplace returns the marketplace (TLD) of an Amazon host ("www.amazon.co.uk" is "co.uk")
+//Only known marketplaces are found, so hosts like "www.amazon.com.evil.com" aren't
+func hostMarketplace(host string) (marketplace string, found bool) {
+	match := marketplaceHostRegex.FindStringSubmatch(host)
+	if match == nil {
+		return "", false
+	}
+	marketplace = strings.ToLower(match[1])
+	_, found = marketplaces[marketplace]
+	return marketplace, found
+}
+
+//CanonicalProductURL returns https://www.amazon.<marketplace>/dp/<asin>
+func CanonicalProductURL(marketplace string, asin string) *url.URL {
+	return marketplaceURL(marketplace, fmt.Sprintf("/dp/%s", asin))
+}
+
+//marketplaceURL returns https://www.amazon.<marketplace><path>, defaulting to DefaultMarketplace
+func marketplaceURL(marketplace string, path string) *url.URL {
+	if len(marketplace) == 0 {
+		marketplace = DefaultMarketplace
+	}
+	return &url.URL{
+		Scheme: "https",
+		Host:   fmt.Sprintf("www.amazon.%s", marketplace),
+		Path:   path,
+	}
+}
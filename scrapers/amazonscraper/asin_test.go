@@ -0,0 +1,84 @@
+package amazonscraper
+
+import "testing"
+
+func TestExtractASIN(t *testing.T) {
+	testTable := []struct {
+		input       string
+		marketplace string
+		asin        string
+		found       bool
+	}{
+		{
+			input: "",
+			found: false,
+		},
+		{
+			input:       "https://www.amazon.com/Acer-R240HY-bidx-23-8-Inch-Widescreen/dp/B0148NNKTC/ref=as_li_ss_tl?ie=UTF8&linkCode=sl1",
+			marketplace: "com",
+			asin:        "B0148NNKTC",
+			found:       true,
+		},
+		{
+			input:       "https://www.amazon.com/dp/B0148NNKTC",
+			marketplace: "com",
+			asin:        "B0148NNKTC",
+			found:       true,
+		},
+		{
+			input:       "https://www.amazon.com/gp/product/B0148NNKTC?psc=1",
+			marketplace: "com",
+			asin:        "B0148NNKTC",
+			found:       true,
+		},
+		{
+			input:       "amazon.co.uk/gp/aw/d/b0148nnktc",
+			marketplace: "co.uk",
+			asin:        "B0148NNKTC",
+			found:       true,
+		},
+		{
+			input:       "https://smile.amazon.de/exec/obidos/ASIN/3426281554/",
+			marketplace: "de",
+			asin:        "3426281554",
+			found:       true,
+		},
+		{
+			input:       "https://www.amazon.ca/HP-13-AQ1001CA-Laptop-i5-1035G1-7YZ81UA/dp/B0899J7B28/ref=br_msw_pdt-4/140-4680546-1561768?_encoding=UTF8",
+			marketplace: "ca",
+			asin:        "B0899J7B28",
+			found:       true,
+		},
+		{
+			input: "https://www.amazon.com/gp/help/customer/display.html",
+			found: false,
+		},
+		{
+			input: "https://www.notamazon.com/dp/B0148NNKTC",
+			found: false,
+		},
+		{
+			input: "https://www.amazon.com.evil.com/dp/B0148NNKTC",
+			found: false,
+		},
+		{
+			input: "https://www.amazon.notastore/dp/B0148NNKTC",
+			found: false,
+		},
+	}
+
+	for _, test := range testTable {
+		marketplace, asin, found := ExtractASIN(test.input)
+		if marketplace != test.marketplace || asin != test.asin || found != test.found {
+			t.Errorf("Input: %v Expected: %v %v %v Result: %v %v %v", test.input, test.marketplace, test.asin, test.found, marketplace, asin, found)
+		}
+	}
+}
+
+func TestCanonicalProductURL(t *testing.T) {
+	result := CanonicalProductURL("co.uk", "B0148NNKTC").String()
+	expected := "https://www.amazon.co.uk/dp/B0148NNKTC"
+	if result != expected {
+		t.Errorf("Expected: %v Result: %v", expected, result)
+	}
+}
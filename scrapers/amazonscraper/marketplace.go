@@ -0,0 +1,44 @@
+package amazonscraper
+
+import (
+	"github.com/programmingparody/amazing-bot/scrapers"
+)
+
+//Marketplace settings used while parsing a regional Amazon store
+type Marketplace struct {
+	Locale    scrapers.Locale
+	Currency  string          //ISO 4217 code used when a price has no recognisable currency symbol ("$")
+	SalesRank SalesRankFormat //How the store writes Best Sellers Ranks. The zero value is englishSalesRank
+}
+
+//marketplaces maps an Amazon TLD to its Marketplace
+var marketplaces = map[string]Marketplace{
+	"com":    {Locale: scrapers.LocaleEnglish, Currency: "USD"},
+	"ca":     {Locale: scrapers.LocaleEnglish, Currency: "CAD"},
+	"com.mx": {Locale: scrapers.LocaleEnglish, Currency: "MXN"},
+	"com.br": {Locale: scrapers.LocaleEuropean, Currency: "BRL"},
+	"co.uk":  {Locale: scrapers.LocaleEnglish, Currency: "GBP"},
+	"de":     {Locale: scrapers.LocaleEuropean, Currency: "EUR", SalesRank: germanSalesRank},
+	"fr":     {Locale: scrapers.LocaleSpaced, Currency: "EUR", SalesRank: frenchSalesRank},
+	"it":     {Locale: scrapers.LocaleEuropean, Currency: "EUR", SalesRank: italianSalesRank},
+	"es":     {Locale: scrapers.LocaleEuropean, Currency: "EUR", SalesRank: spanishSalesRank},
+	"nl":     {Locale: scrapers.LocaleEuropean, Currency: "EUR"},
+	"se":     {Locale: scrapers.LocaleSpaced, Currency: "SEK"},
+	"pl":     {Locale: scrapers.LocaleSpaced, Currency: "PLN"},
+	"com.tr": {Locale: scrapers.LocaleEuropean, Currency: "TRY"},
+	"co.jp":  {Locale: scrapers.LocaleEnglish, Currency: "JPY", SalesRank: japaneseSalesRank},
+	"in":     {Locale: scrapers.LocaleEnglish, Currency: "INR"},
+	"com.au": {Locale: scrapers.LocaleEnglish, Currency: "AUD"},
+	"sg":     {Locale: scrapers.LocaleEnglish, Currency: "SGD"},
+	"ae":     {Locale: scrapers.LocaleEnglish, Currency: "AED"},
+	"sa":     {Locale: scrapers.LocaleEnglish, Currency: "SAR"},
+	"eg":     {Locale: scrapers.LocaleEnglish, Currency: "EGP"},
+}
+
+//MarketplaceFromTLD returns the Marketplace of an Amazon TLD ("com", "de", ...), defaulting to amazon.com
+func MarketplaceFromTLD(tld string) Marketplace {
+	if marketplace, found := marketplaces[tld]; found {
+		return marketplace
+	}
+	return marketplaces[DefaultMarketplace]
+}
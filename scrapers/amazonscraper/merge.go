@@ -0,0 +1,79 @@
+package amazonscraper
+
+import (
+	"reflect"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+//Parsing strategies, recorded per field in Product.Sources
+const (
+	SourceHTML    = "html"    //CSS selectors on the page's elements
+	SourceJSONLD  = "json-ld" //<script type="application/ld+json"> structured data
+	SourceAState  = "a-state" //<script type="a-state"> JSON blobs
+	SourceTwister = "twister" //Twister (variation / buying options) price JSON
+)
+
+type parseStrategy struct {
+	Source string
+	Parse  func(document *goquery.Document, settings Marketplace) *Product
+}
+
+//fieldGroups are merged as one unit, all from the first strategy to find the group's first field
+//A price and its currency and original price only make sense together
+var fieldGroups = [][]string{
+	{"Price", "Currency", "OriginalPrice"},
+}
+
+//mergeStrategies runs every strategy and merges their results field by field
+//The first strategy to find a value for a field wins, and is recorded in Product.Sources
+func mergeStrategies(document *goquery.Document, settings Marketplace, strategies []parseStrategy) *Product {
+	result := &Product{Sources: make(map[string]string)}
+	resultValue := reflect.ValueOf(result).Elem()
+	productType := resultValue.Type()
+
+	grouped := map[string]bool{}
+	for _, group := range fieldGroups {
+		for _, name := range group {
+			grouped[name] = true
+		}
+	}
+
+	for _, strategy := range strategies {
+		parsed := strategy.Parse(document, settings)
+		if parsed == nil {
+			continue
+		}
+		parsedValue := reflect.ValueOf(parsed).Elem()
+
+		for _, group := range fieldGroups {
+			if !isEmptyValue(resultValue.FieldByName(group[0])) || isEmptyValue(parsedValue.FieldByName(group[0])) {
+				continue
+			}
+			for _, name := range group {
+				resultValue.FieldByName(name).Set(parsedValue.FieldByName(name))
+				if !isEmptyValue(parsedValue.FieldByName(name)) {
+					result.Sources[name] = strategy.Source
+				}
+			}
+		}
+
+		for i := 0; i < productType.NumField(); i++ {
+			name := productType.Field(i).Name
+			if name == "Sources" || grouped[name] || !isEmptyValue(resultValue.Field(i)) || isEmptyValue(parsedValue.Field(i)) {
+				continue
+			}
+			resultValue.Field(i).Set(parsedValue.Field(i))
+			result.Sources[name] = strategy.Source
+		}
+	}
+	return result
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
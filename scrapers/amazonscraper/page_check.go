@@ -0,0 +1,52 @@
+package amazonscraper
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/programmingparody/amazing-bot/scrapers"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+//Errors returned by ParseProductHTML when Amazon didn't serve a product page
+var (
+	ErrCaptcha        = fmt.Errorf("[AmazonScraper] CAPTCHA page: %w", scrapers.ErrBlocked)
+	ErrRobotCheck     = fmt.Errorf("[AmazonScraper] Robot check page: %w", scrapers.ErrBlocked)
+	ErrNotFound       = fmt.Errorf("[AmazonScraper] Page not found (Dogs of Amazon): %w", scrapers.ErrProductNotFound)
+	ErrNotProductPage = fmt.Errorf("[AmazonScraper] Not a product page: %w", scrapers.ErrNotProductPage)
+)
+
+//checkPage returns one of the sentinel errors above when the document isn't a product page
+func checkPage(document *goquery.Document, selectors *SelectorSet) error {
+	if error := checkBlocked(document); error != nil {
+		return error
+	}
+	title := strings.ToLower(document.Find("title").First().Text())
+	bodyText := strings.ToLower(document.Find("body").Text())
+
+	_, dogsFound := findFallback(document, `a[href*="dogsofamazon"]`, `img[alt*="Dogs of Amazon"]`)
+	if dogsFound || strings.Contains(title, "page not found") || strings.Contains(bodyText, "we couldn't find that page") {
+		return ErrNotFound
+	}
+
+	if _, productFound := findFallback(document, selectors.Get(SelectorProductPage)...); !productFound {
+		return ErrNotProductPage
+	}
+	return nil
+}
+
+//checkBlocked returns ErrCaptcha or ErrRobotCheck when Amazon served a bot check instead of the page
+func checkBlocked(document *goquery.Document) error {
+	title := strings.ToLower(document.Find("title").First().Text())
+	bodyText := document.Find("body").Text()
+
+	_, captchaFound := findFallback(document, `form[action*="validateCaptcha"]`, "#captchacharacters")
+	if captchaFound || strings.Contains(bodyText, "Type the characters you see in this image") {
+		return ErrCaptcha
+	}
+	if strings.Contains(title, "robot check") || strings.Contains(bodyText, "To discuss automated access to Amazon data") {
+		return ErrRobotCheck
+	}
+	return nil
+}
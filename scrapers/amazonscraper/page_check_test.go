@@ -0,0 +1,65 @@
+package amazonscraper
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/programmingparody/amazing-bot/scrapers"
+)
+
+func TestParseProductHTMLPageChecks(t *testing.T) {
+	testTable := []struct {
+		name     string
+		html     string
+		expected error
+		category error
+	}{
+		{
+			name:     "captcha",
+			html:     `<html><head><title>Amazon.com</title></head><body><form method="get" action="/errors/validateCaptcha"><input id="captchacharacters"></form></body></html>`,
+			expected: ErrCaptcha,
+			category: scrapers.ErrBlocked,
+		},
+		{
+			name:     "robot check",
+			html:     `<html><head><title>Robot Check</title></head><body>To discuss automated access to Amazon data please contact api-services-support@amazon.com.</body></html>`,
+			expected: ErrRobotCheck,
+			category: scrapers.ErrBlocked,
+		},
+		{
+			name:     "dogs of amazon",
+			html:     `<html><head><title>Page Not Found</title></head><body><a href="/dogsofamazon"><img alt="Dogs of Amazon"></a></body></html>`,
+			expected: ErrNotFound,
+			category: scrapers.ErrProductNotFound,
+		},
+		{
+			name:     "page not found text",
+			html:     `<html><head><title>Amazon.com</title></head><body><h1>Sorry! We couldn't find that page. Try searching or go to Amazon's home page.</h1></body></html>`,
+			expected: ErrNotFound,
+			category: scrapers.ErrProductNotFound,
+		},
+		{
+			name:     "not a product",
+			html:     `<html><head><title>Amazon.com: Online Shopping</title></head><body><div id="nav-main"></div></body></html>`,
+			expected: ErrNotProductPage,
+			category: scrapers.ErrNotProductPage,
+		},
+		{
+			name: "product",
+			html: `<html><head><title>Amazon.com: Thing</title></head><body><span id="productTitle">Thing</span></body></html>`,
+		},
+	}
+
+	for _, test := range testTable {
+		product, error := ParseProductHTML([]byte(test.html))
+		if error != test.expected {
+			t.Errorf("%v: Expected: %v Result: %v", test.name, test.expected, error)
+		}
+		if test.category != nil && !errors.Is(error, test.category) {
+			t.Errorf("%v: %v is not a %v", test.name, error, test.category)
+		}
+		if test.expected != nil && product != nil {
+			t.Errorf("%v: Expected no product, Result: %v", test.name, product)
+		}
+	}
+}
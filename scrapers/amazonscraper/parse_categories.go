@@ -0,0 +1,77 @@
+package amazonscraper
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+//SalesRank is a Best Sellers Rank entry ("#3 in Mechanical Keyboards")
+type SalesRank struct {
+	Rank     uint
+	Category string
+}
+
+//parseBreadcrumbs returns the category path, from the broadest category to the narrowest
+func parseBreadcrumbs(document *goquery.Document, selectors *SelectorSet) []string {
+	categories := []string{}
+	links, _ := findFallback(document, selectors.Get(SelectorBreadcrumbs)...)
+	links.Each(func(_ int, s *goquery.Selection) {
+		category := strings.Join(strings.Fields(s.Text()), " ")
+		if len(category) > 0 {
+			categories = append(categories, category)
+		}
+	})
+	return categories
+}
+
+//SalesRankFormat is how a marketplace writes its Best Sellers Ranks
+type SalesRankFormat struct {
+	Label string         //Starts the ranks ("Best Sellers Rank")
+	Rank  *regexp.Regexp //Matches what comes before each rank's category, capturing the rank ("#1,234 in ")
+}
+
+//Sales rank formats of the marketplaces
+var (
+	englishSalesRank  = SalesRankFormat{Label: "Best Sellers Rank", Rank: regexp.MustCompile(`#\s*([\d.,]+)\s+in\s+`)}                 //#1,234 in Electronics
+	germanSalesRank   = SalesRankFormat{Label: "Bestseller-Rang", Rank: regexp.MustCompile(`Nr\.\s*([\d.]+)\s+in\s+`)}                 //Nr. 1.234 in Elektronik & Foto
+	frenchSalesRank   = SalesRankFormat{Label: "Classement des meilleures ventes", Rank: regexp.MustCompile(`(\d[\d\s]*)\s+en\s+`)}    //1 234 en Informatique
+	spanishSalesRank  = SalesRankFormat{Label: "Clasificación en los más vendidos", Rank: regexp.MustCompile(`nº\s*([\d.]+)\s+en\s+`)} //nº1.234 en Electrónica
+	italianSalesRank  = SalesRankFormat{Label: "Posizione nella classifica Bestseller", Rank: regexp.MustCompile(`n\.\s*([\d.]+)\s+in\s+`)}
+	japaneseSalesRank = SalesRankFormat{Label: "売れ筋ランキング", Rank: regexp.MustCompile(`([\d,]+)位\s*`)} //1,234位パソコン・周辺機器
+)
+
+//parseBestSellersRanks reads every Best Sellers Rank entry from the detail bullets or the product details table
+func parseBestSellersRanks(document *goquery.Document, selectors *SelectorSet, settings Marketplace) []SalesRank {
+	format := settings.SalesRank
+	if format.Rank == nil {
+		format = englishSalesRank
+	}
+	ranks := []SalesRank{}
+	rows, _ := findFallback(document, selectors.Get(SelectorBestSellersRank)...)
+	rows.EachWithBreak(func(_ int, row *goquery.Selection) bool {
+		text := strings.Join(strings.Fields(row.Text()), " ")
+		index := strings.Index(text, format.Label)
+		if index < 0 {
+			return true
+		}
+		text = text[index+len(format.Label):]
+		matches := format.Rank.FindAllStringSubmatchIndex(text, -1)
+		for i, match := range matches {
+			//The category runs until the next rank, without the "(See Top 100 in ...)" link
+			end := len(text)
+			if i+1 < len(matches) {
+				end = matches[i+1][0]
+			}
+			category := strings.SplitN(text[match[1]:end], "(", 2)[0]
+			ranks = append(ranks, SalesRank{
+				Rank:     uint(numbersFromStringFallback(text[match[2]:match[3]], settings.Locale, 0)[0]),
+				Category: strings.Trim(category, " -"),
+			})
+		}
+		//The detail bullets and the details table can both be on the page, with the same ranks
+		return len(ranks) == 0
+	})
+	return ranks
+}
@@ -0,0 +1,102 @@
+package amazonscraper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseProductHTMLCategories(t *testing.T) {
+	testTable := []struct {
+		name string
+		html string
+	}{
+		{
+			name: "detail bullets",
+			html: `<div id="detailBulletsWrapper_feature_div"><ul>
+				<li><span>ASIN: B07MPCSHQD</span></li>
+				<li><span><span class="a-text-bold">Best Sellers Rank:</span> #1,234 in Electronics (<a href="#">See Top 100 in Electronics</a>)
+					<ul><li><span>#3 in <a href="#">Mechanical Keyboards</a></span></li></ul>
+				</span></li>
+			</ul></div>`,
+		},
+		{
+			name: "product details table",
+			html: `<table id="productDetails_detailBullets_sections1">
+				<tr><th>ASIN</th><td>B07MPCSHQD</td></tr>
+				<tr><th>Best Sellers Rank</th><td><span><span>#1,234 in Electronics (<a href="#">See Top 100 in Electronics</a>)</span><br><span>#3 in <a href="#">Mechanical Keyboards</a></span></span></td></tr>
+			</table>`,
+		},
+	}
+
+	expectedCategories := []string{"Electronics", "Computers & Accessories", "Keyboards"}
+	expectedRanks := []SalesRank{
+		{Rank: 1234, Category: "Electronics"},
+		{Rank: 3, Category: "Mechanical Keyboards"},
+	}
+
+	for _, test := range testTable {
+		html := `<html><body><span id="productTitle">Keyboard</span>
+		<div id="wayfinding-breadcrumbs_feature_div"><ul>
+			<li><a> Electronics </a></li><li class="a-breadcrumb-divider">›</li>
+			<li><a> Computers &amp; Accessories </a></li><li class="a-breadcrumb-divider">›</li>
+			<li><a> Keyboards </a></li>
+		</ul></div>` + test.html + `</body></html>`
+
+		product, error := ParseProductHTML([]byte(html))
+		if error != nil {
+			t.Fatal(error)
+		}
+		if !reflect.DeepEqual(product.Categories, expectedCategories) {
+			t.Errorf("%v: Expected: %v Result: %v", test.name, expectedCategories, product.Categories)
+		}
+		if !reflect.DeepEqual(product.BestSellersRanks, expectedRanks) {
+			t.Errorf("%v: Expected: %v Result: %v", test.name, expectedRanks, product.BestSellersRanks)
+		}
+	}
+}
+
+func TestParseBestSellersRanksMarketplaces(t *testing.T) {
+	testTable := []struct {
+		marketplace string
+		bullet      string
+		expected    []SalesRank
+	}{
+		{
+			marketplace: "de",
+			bullet:      `Amazon Bestseller-Rang: Nr. 1.234 in Elektronik &amp; Foto (<a href="#">Siehe Top 100 in Elektronik &amp; Foto</a>) <ul><li>Nr. 3 in <a href="#">Tastaturen</a></li></ul>`,
+			expected:    []SalesRank{{Rank: 1234, Category: "Elektronik & Foto"}, {Rank: 3, Category: "Tastaturen"}},
+		},
+		{
+			marketplace: "fr",
+			bullet:      `Classement des meilleures ventes d'Amazon : 1 234 en Informatique (<a href="#">Voir les 100 premiers en Informatique</a>) <ul><li>3 en <a href="#">Claviers</a></li></ul>`,
+			expected:    []SalesRank{{Rank: 1234, Category: "Informatique"}, {Rank: 3, Category: "Claviers"}},
+		},
+		{
+			marketplace: "es",
+			bullet:      `Clasificación en los más vendidos de Amazon: nº1.234 en Informática (<a href="#">Ver el Top 100 en Informática</a>) <ul><li>nº3 en <a href="#">Teclados</a></li></ul>`,
+			expected:    []SalesRank{{Rank: 1234, Category: "Informática"}, {Rank: 3, Category: "Teclados"}},
+		},
+		{
+			marketplace: "it",
+			bullet:      `Posizione nella classifica Bestseller di Amazon: n. 1.234 in Informatica (<a href="#">Visualizza i Top 100 nella categoria Informatica</a>) <ul><li>n. 3 in <a href="#">Tastiere</a></li></ul>`,
+			expected:    []SalesRank{{Rank: 1234, Category: "Informatica"}, {Rank: 3, Category: "Tastiere"}},
+		},
+		{
+			marketplace: "co.jp",
+			bullet:      `Amazon 売れ筋ランキング: - 1,234位パソコン・周辺機器 (<a href="#">の売れ筋ランキングを見るパソコン・周辺機器</a>) <ul><li>- 3位<a href="#">キーボード</a></li></ul>`,
+			expected:    []SalesRank{{Rank: 1234, Category: "パソコン・周辺機器"}, {Rank: 3, Category: "キーボード"}},
+		},
+	}
+
+	for _, test := range testTable {
+		html := `<html><body><span id="productTitle">Keyboard</span>
+		<div id="detailBulletsWrapper_feature_div"><ul><li><span>` + test.bullet + `</span></li></ul></div></body></html>`
+		product, error := ParseProductHTMLFromMarketplace([]byte(html), test.marketplace)
+		if error != nil {
+			t.Fatal(error)
+		}
+		if !reflect.DeepEqual(product.BestSellersRanks, test.expected) {
+			t.Errorf("%v: Expected: %v Result: %v", test.marketplace, test.expected, product.BestSellersRanks)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package amazonscraper
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/programmingparody/amazing-bot/scrapers"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+//Coupon that can be clipped on a product page. Either Percent or Amount is set
+type Coupon struct {
+	Percent float32
+	Amount  float32
+}
+
+//Deal badge on a product page ("Lightning Deal", "Limited time deal", ...)
+type Deal struct {
+	Badge  string
+	EndsAt time.Time //Zero when the page doesn't say when the deal ends
+}
+
+//now is replaced in tests
+var now = time.Now
+
+var couponPercentRegex = regexp.MustCompile(`(\d+(?:[.,]\d+)?)\s*%`)
+
+//parseCoupon reads "Apply 15% coupon" and "Save $5.00 with coupon" badges
+func parseCoupon(document *goquery.Document, selectors *SelectorSet, settings Marketplace) *Coupon {
+	element, found := findFallback(document, selectors.Get(SelectorCoupon)...)
+	text := strings.Join(strings.Fields(element.First().Text()), " ")
+	if !found || !strings.Contains(strings.ToLower(text), "coupon") {
+		return nil
+	}
+
+	if match := couponPercentRegex.FindStringSubmatch(text); match != nil {
+		percent, _ := strconv.ParseFloat(strings.Replace(match[1], ",", ".", 1), 32)
+		return &Coupon{Percent: float32(percent)}
+	}
+	if amount, _, found := scrapers.ParsePrice(text, settings.Locale, settings.Currency); found {
+		return &Coupon{Amount: float32(amount)}
+	}
+	return nil
+}
+
+var dealDurationRegex = regexp.MustCompile(`(?i)ends in\s+(?:(\d+):(\d{2}):(\d{2})|(?:(\d+)\s*h\w*)?\s*(?:(\d+)\s*m\w*)?\s*(?:(\d+)\s*s\w*)?)`)
+
+//parseDeal reads the deal badge, and when the deal ends from its "Ends in 5h 32m" / "Ends in 05:32:11" timer
+func parseDeal(document *goquery.Document, selectors *SelectorSet) *Deal {
+	badgeElement, found := findFallback(document, selectors.Get(SelectorDealBadge)...)
+	badge := strings.Join(strings.Fields(badgeElement.First().Text()), " ")
+	if !found || len(badge) == 0 {
+		return nil
+	}
+	deal := &Deal{Badge: badge}
+
+	timerElement, _ := findFallback(document, selectors.Get(SelectorDealTimer)...)
+	match := dealDurationRegex.FindStringSubmatch(strings.Join(strings.Fields(timerElement.Text()), " "))
+	if match == nil {
+		return deal
+	}
+	hours, minutes, seconds := match[1]+match[4], match[2]+match[5], match[3]+match[6]
+	if len(hours+minutes+seconds) == 0 {
+		return deal
+	}
+	duration := time.Duration(0)
+	for _, part := range []struct {
+		value string
+		unit  time.Duration
+	}{{hours, time.Hour}, {minutes, time.Minute}, {seconds, time.Second}} {
+		value, _ := strconv.Atoi(part.value)
+		duration += time.Duration(value) * part.unit
+	}
+	deal.EndsAt = now().Add(duration).Truncate(time.Second)
+	return deal
+}
+
+//parseSubscribeAndSavePrice reads the Subscribe & Save price (0 when the product has none)
+func parseSubscribeAndSavePrice(document *goquery.Document, selectors *SelectorSet, settings Marketplace) float32 {
+	element, _ := findFallback(document, selectors.Get(SelectorSubscribeAndSave)...)
+	price, _, _ := scrapers.ParsePrice(element.First().Text(), settings.Locale, settings.Currency)
+	return float32(price)
+}
@@ -0,0 +1,70 @@
+package amazonscraper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseProductHTMLDeals(t *testing.T) {
+	fixedNow := time.Date(2020, 11, 27, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return fixedNow }
+	defer func() { now = time.Now }()
+
+	testTable := []struct {
+		name     string
+		html     string
+		coupon   *Coupon
+		deal     *Deal
+		snsPrice float32
+	}{
+		{
+			name:   "percent coupon",
+			html:   `<label id="couponTextpctch123">Apply 15% coupon </label>`,
+			coupon: &Coupon{Percent: 15},
+		},
+		{
+			name:   "amount coupon",
+			html:   `<div id="promoPriceBlockMessage_feature_div"><span>Save $5.00 with coupon</span></div>`,
+			coupon: &Coupon{Amount: 5},
+		},
+		{
+			name: "promotion without coupon",
+			html: `<div id="promoPriceBlockMessage_feature_div"><span>Buy 2, save 10%</span></div>`,
+		},
+		{
+			name: "lightning deal with timer",
+			html: `<div id="dealBadge_feature_div"><span class="dealBadge"> Lightning Deal </span></div>
+				<span id="deal_expiry_timer_B07MPCSHQD">Ends in 05:32:11</span>`,
+			deal: &Deal{Badge: "Lightning Deal", EndsAt: fixedNow.Add(5*time.Hour + 32*time.Minute + 11*time.Second)},
+		},
+		{
+			name: "limited time deal",
+			html: `<div id="dealBadge_feature_div"><span class="dealBadge">Limited time deal</span></div>
+				<div id="dealBadgeSupportingText">Ends in 2h 15m</div>`,
+			deal: &Deal{Badge: "Limited time deal", EndsAt: fixedNow.Add(2*time.Hour + 15*time.Minute)},
+		},
+		{
+			name:     "subscribe and save",
+			html:     `<span id="sns-base-price">$18.99</span>`,
+			snsPrice: 18.99,
+		},
+	}
+
+	for _, test := range testTable {
+		html := `<html><body><span id="productTitle">Coffee</span><span id="priceblock_ourprice">$19.99</span>` + test.html + `</body></html>`
+		product, error := ParseProductHTML([]byte(html))
+		if error != nil {
+			t.Fatal(error)
+		}
+		if !reflect.DeepEqual(product.Coupon, test.coupon) {
+			t.Errorf("%v: Expected coupon: %+v Result: %+v", test.name, test.coupon, product.Coupon)
+		}
+		if !reflect.DeepEqual(product.Deal, test.deal) {
+			t.Errorf("%v: Expected deal: %+v Result: %+v", test.name, test.deal, product.Deal)
+		}
+		if product.SubscribeAndSavePrice != test.snsPrice {
+			t.Errorf("%v: Expected Subscribe & Save price: %v Result: %v", test.name, test.snsPrice, product.SubscribeAndSavePrice)
+		}
+	}
+}
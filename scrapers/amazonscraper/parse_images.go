@@ -0,0 +1,71 @@
+package amazonscraper
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+//Image of a product's gallery
+type Image struct {
+	URL      string //Hi-res image when the page has one. For videos, the video's thumbnail
+	VideoURL string //Set when the image is the thumbnail of a video
+}
+
+//imageSizeRegex matches the size modifiers of an image URL ("._AC_US40_" in "/I/71abc._AC_US40_.jpg")
+var imageSizeRegex = regexp.MustCompile(`\._[^/]*_\.(jpg|jpeg|png|gif)$`)
+
+//parseGallery reads every gallery image (from the image block's colorImages data, falling back to the thumbnails) and video
+func parseGallery(document *goquery.Document, selectors *SelectorSet) []Image {
+	images := []Image{}
+	document.Find("script").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		script := s.Text()
+		index := strings.Index(script, "colorImages")
+		if index < 0 {
+			return true
+		}
+		var colorImages []struct {
+			HiRes string `json:"hiRes"`
+			Large string `json:"large"`
+		}
+		scriptValue(script[index:], "initial", &colorImages)
+		for _, image := range colorImages {
+			url := image.HiRes
+			if len(url) == 0 {
+				url = image.Large
+			}
+			if len(url) > 0 {
+				images = append(images, Image{URL: url})
+			}
+		}
+		return len(images) == 0
+	})
+
+	if len(images) == 0 {
+		thumbnails, _ := findFallback(document, selectors.Get(SelectorGalleryThumbnails)...)
+		thumbnails.Each(func(_ int, s *goquery.Selection) {
+			url := s.AttrOr("src", "")
+			if len(url) > 0 && !strings.HasPrefix(url, "data:") {
+				images = append(images, Image{URL: imageSizeRegex.ReplaceAllString(url, ".$1")})
+			}
+		})
+	}
+
+	document.Find("script").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		var videos []struct {
+			ThumbURL string `json:"thumbUrl"`
+			URL      string `json:"url"`
+		}
+		if !scriptValue(s.Text(), "videos", &videos) {
+			return true
+		}
+		for _, video := range videos {
+			if len(video.ThumbURL) > 0 && len(video.URL) > 0 {
+				images = append(images, Image{URL: video.ThumbURL, VideoURL: video.URL})
+			}
+		}
+		return false
+	})
+	return images
+}
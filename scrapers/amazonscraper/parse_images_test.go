@@ -0,0 +1,59 @@
+package amazonscraper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseProductHTMLGallery(t *testing.T) {
+	testTable := []struct {
+		name     string
+		html     string
+		expected []Image
+	}{
+		{
+			name: "colorImages and videos",
+			html: `<script type="text/javascript">
+				P.when('A').register("ImageBlockATF", function(A){
+					var data = {
+						'colorImages': { 'initial': [{"hiRes":"https://m.media-amazon.com/images/I/71a.jpg","thumb":"https://m.media-amazon.com/images/I/41a._AC_US40_.jpg","large":"https://m.media-amazon.com/images/I/41a.jpg"},{"hiRes":null,"large":"https://m.media-amazon.com/images/I/41b.jpg"}]},
+						'colorToAsin': {'initial': {}},
+						'videos': [{"thumbUrl":"https://m.media-amazon.com/images/I/51v.jpg","url":"https://m.media-amazon.com/images/S/video.mp4","title":"Unboxing"}]
+					};
+					A.trigger('P.AboveTheFold');
+					return data;
+				});
+			</script>`,
+			expected: []Image{
+				{URL: "https://m.media-amazon.com/images/I/71a.jpg"},
+				{URL: "https://m.media-amazon.com/images/I/41b.jpg"},
+				{URL: "https://m.media-amazon.com/images/I/51v.jpg", VideoURL: "https://m.media-amazon.com/images/S/video.mp4"},
+			},
+		},
+		{
+			name: "thumbnails",
+			html: `<div id="altImages"><ul>
+				<li class="a-spacing-small item imageThumbnail"><img src="https://m.media-amazon.com/images/I/41a._AC_US40_.jpg"></li>
+				<li class="a-spacing-small item imageThumbnail"><img src="https://m.media-amazon.com/images/I/41b._SS40_.jpg"></li>
+			</ul></div>`,
+			expected: []Image{
+				{URL: "https://m.media-amazon.com/images/I/41a.jpg"},
+				{URL: "https://m.media-amazon.com/images/I/41b.jpg"},
+			},
+		},
+	}
+
+	for _, test := range testTable {
+		html := `<html><body><span id="productTitle">Product</span>` + test.html + `</body></html>`
+		product, error := ParseProductHTML([]byte(html))
+		if error != nil {
+			t.Fatal(error)
+		}
+		if !reflect.DeepEqual(product.Images, test.expected) {
+			t.Errorf("%v: Expected: %+v Result: %+v", test.name, test.expected, product.Images)
+		}
+		if product.ImageURL != test.expected[0].URL {
+			t.Errorf("%v: Expected image URL: %v Result: %v", test.name, test.expected[0].URL, product.ImageURL)
+		}
+	}
+}
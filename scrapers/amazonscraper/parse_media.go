@@ -0,0 +1,171 @@
+package amazonscraper
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+//ProductKind decides which kind-specific fields a Product has
+type ProductKind string
+
+//Kinds of products. Every kind other than KindGeneral has Product.Media
+const (
+	KindGeneral ProductKind = "general"
+	KindBook    ProductKind = "book"
+	KindKindle  ProductKind = "kindle"
+	KindMusic   ProductKind = "music"
+	KindVideo   ProductKind = "video"
+)
+
+//Media holds the fields of books, Kindle editions, music and video
+type Media struct {
+	Contributors []Contributor
+	Format       string //"Paperback", "Kindle Edition", "Vinyl", "Blu-ray", ...
+	Pages        uint
+	Publisher    string //Publisher, record label or studio
+	ReleaseDate  string //As written on the page ("October 1, 2019")
+	Language     string
+	ISBN10       string
+	ISBN13       string
+}
+
+//Contributor to a book or media ("Author", "Artist", "Narrator", "Director", ...)
+type Contributor struct {
+	Name string
+	Role string
+}
+
+//kindFormats maps words of a product's format to its kind, checked in order ("Kindle Edition" before "Edition" of a book)
+var kindFormats = []struct {
+	word string
+	kind ProductKind
+}{
+	{"kindle", KindKindle},
+	{"paperback", KindBook},
+	{"hardcover", KindBook},
+	{"board book", KindBook},
+	{"mass market", KindBook},
+	{"spiral-bound", KindBook},
+	{"audiobook", KindBook},
+	{"vinyl", KindMusic},
+	{"audio cd", KindMusic},
+	{"mp3 music", KindMusic},
+	{"cassette", KindMusic},
+	{"blu-ray", KindVideo},
+	{"dvd", KindVideo},
+	{"prime video", KindVideo},
+}
+
+//kindCategories maps the broadest breadcrumb category to a kind, for formats kindFormats doesn't know
+var kindCategories = map[string]ProductKind{
+	"Books":         KindBook,
+	"Kindle Store":  KindKindle,
+	"CDs & Vinyl":   KindMusic,
+	"Digital Music": KindMusic,
+	"Movies & TV":   KindVideo,
+}
+
+var formatDateSeparatorRegex = regexp.MustCompile(`\s+[–-]\s+`)
+
+//directionMarks surround the labels and values of the detail bullets ("Publisher \u200f : \u200e Penguin")
+var directionMarks = strings.NewReplacer("\u200e", "", "\u200f", "")
+
+//parseMedia detects the product's kind and reads its book/media fields (nil for KindGeneral)
+func parseMedia(document *goquery.Document, selectors *SelectorSet, settings Marketplace, categories []string) (ProductKind, *Media) {
+	media := &Media{}
+
+	formatElement, _ := findFallback(document, selectors.Get(SelectorFormat)...)
+	formatParts := formatDateSeparatorRegex.Split(strings.Join(strings.Fields(formatElement.First().Text()), " "), 2)
+	media.Format = formatParts[0]
+	if len(formatParts) > 1 {
+		media.ReleaseDate = formatParts[1]
+	}
+
+	contributorElements, _ := findFallback(document, selectors.Get(SelectorContributors)...)
+	contributorElements.Each(func(_ int, s *goquery.Selection) {
+		name := strings.TrimSpace(s.Find("a").FilterFunction(func(_ int, a *goquery.Selection) bool {
+			return len(strings.TrimSpace(a.Text())) > 0
+		}).First().Text())
+		role := strings.Trim(strings.TrimSpace(s.Find(".contribution").Text()), "(),")
+		if len(name) > 0 {
+			media.Contributors = append(media.Contributors, Contributor{Name: name, Role: strings.TrimSpace(role)})
+		}
+	})
+
+	for label, value := range parseDetails(document, selectors) {
+		switch label {
+		case "Publisher", "Label", "Studio":
+			media.Publisher = strings.TrimSpace(strings.SplitN(strings.SplitN(value, " (", 2)[0], ";", 2)[0])
+			if date := strings.SplitN(value, " (", 2); len(media.ReleaseDate) == 0 && len(date) > 1 {
+				media.ReleaseDate = strings.TrimSuffix(date[1], ")")
+			}
+		case "Publication date", "Release date", "Original Release Date":
+			media.ReleaseDate = value
+		case "Print length", "Paperback", "Hardcover", "Pages":
+			if strings.Contains(value, "page") {
+				media.Pages = uint(numbersFromStringFallback(value, settings.Locale, 0)[0])
+			}
+		case "Language":
+			media.Language = value
+		case "ISBN-10":
+			media.ISBN10 = value
+		case "ISBN-13":
+			media.ISBN13 = value
+		case "Format", "Media Format":
+			if len(media.Format) == 0 {
+				media.Format = value
+			}
+		}
+	}
+
+	kind := kindOf(media, categories)
+	if kind == KindGeneral {
+		return kind, nil
+	}
+	return kind, media
+}
+
+//kindOf detects the kind from the format, then the broadest category, then whether there's an ISBN
+func kindOf(media *Media, categories []string) ProductKind {
+	format := strings.ToLower(media.Format)
+	for _, f := range kindFormats {
+		if strings.Contains(format, f.word) {
+			return f.kind
+		}
+	}
+	if len(categories) > 0 {
+		if kind, found := kindCategories[categories[0]]; found {
+			return kind
+		}
+	}
+	if len(media.ISBN10) > 0 || len(media.ISBN13) > 0 {
+		return KindBook
+	}
+	return KindGeneral
+}
+
+//parseDetails reads the detail bullets ("Publisher : Penguin") or the product details table into label -> value
+func parseDetails(document *goquery.Document, selectors *SelectorSet) map[string]string {
+	details := map[string]string{}
+	rows, _ := findFallback(document, selectors.Get(SelectorProductDetails)...)
+	rows.Each(func(_ int, row *goquery.Selection) {
+		var label, value string
+		if header := row.Find("th"); header.Length() > 0 {
+			label, value = header.First().Text(), row.Find("td").First().Text()
+		} else {
+			parts := strings.SplitN(row.Text(), ":", 2)
+			if len(parts) < 2 {
+				return
+			}
+			label, value = parts[0], parts[1]
+		}
+		label = strings.TrimSuffix(strings.Join(strings.Fields(directionMarks.Replace(label)), " "), ":")
+		value = strings.Join(strings.Fields(directionMarks.Replace(value)), " ")
+		if _, found := details[label]; !found && len(label) > 0 && len(value) > 0 {
+			details[label] = value
+		}
+	})
+	return details
+}
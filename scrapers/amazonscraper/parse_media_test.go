@@ -0,0 +1,87 @@
+package amazonscraper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseProductHTMLMedia(t *testing.T) {
+	testTable := []struct {
+		name  string
+		html  string
+		kind  ProductKind
+		media *Media
+	}{
+		{
+			name: "paperback with detail bullets",
+			html: `<div id="bylineInfo">
+					<span class="author"><a class="a-link-normal" href="#">Frank Herbert</a> <span class="contribution"><span>(Author)</span></span>,</span>
+					<span class="author"><a class="a-link-normal" href="#">Brian Herbert</a> <span class="contribution"><span>(Introduction)</span></span></span>
+				</div>
+				<span id="productSubtitle">Paperback – August 2, 2005</span>
+				<div id="detailBullets_feature_div"><ul>
+					<li><span><span class="a-text-bold">Publisher &#x200f; : &#x200e; </span><span>Ace; Deluxe edition (August 2, 2005)</span></span></li>
+					<li><span><span class="a-text-bold">Language &#x200f; : &#x200e; </span><span>English</span></span></li>
+					<li><span><span class="a-text-bold">Paperback &#x200f; : &#x200e; </span><span>896 pages</span></span></li>
+					<li><span><span class="a-text-bold">ISBN-10 &#x200f; : &#x200e; </span><span>0441013597</span></span></li>
+					<li><span><span class="a-text-bold">ISBN-13 &#x200f; : &#x200e; </span><span>978-0441013593</span></span></li>
+				</ul></div>`,
+			kind: KindBook,
+			media: &Media{
+				Contributors: []Contributor{{Name: "Frank Herbert", Role: "Author"}, {Name: "Brian Herbert", Role: "Introduction"}},
+				Format:       "Paperback",
+				Pages:        896,
+				Publisher:    "Ace",
+				ReleaseDate:  "August 2, 2005",
+				Language:     "English",
+				ISBN10:       "0441013597",
+				ISBN13:       "978-0441013593",
+			},
+		},
+		{
+			name: "kindle edition with details table",
+			html: `<span id="productBinding">Kindle Edition</span>
+				<table id="productDetails_detailBullets_sections1">
+					<tr><th>Print length</th><td>412 pages</td></tr>
+					<tr><th>Publication date</th><td>June 1, 2010</td></tr>
+				</table>`,
+			kind:  KindKindle,
+			media: &Media{Format: "Kindle Edition", Pages: 412, ReleaseDate: "June 1, 2010"},
+		},
+		{
+			name: "vinyl",
+			html: `<div id="bylineInfo"><span class="author"><a href="#">Fleetwood Mac</a></span></div>
+				<span id="productSubtitle">Vinyl</span>
+				<div id="detailBullets_feature_div"><ul>
+					<li><span><span class="a-text-bold">Label &#x200f; : &#x200e; </span><span>Rhino/Warner Records</span></span></li>
+					<li><span><span class="a-text-bold">Original Release Date &#x200f; : &#x200e; </span><span>1977</span></span></li>
+				</ul></div>`,
+			kind: KindMusic,
+			media: &Media{
+				Contributors: []Contributor{{Name: "Fleetwood Mac"}},
+				Format:       "Vinyl",
+				Publisher:    "Rhino/Warner Records",
+				ReleaseDate:  "1977",
+			},
+		},
+		{
+			name: "general product",
+			html: `<a id="bylineInfo" href="#">Visit the Acer Store</a>`,
+			kind: KindGeneral,
+		},
+	}
+
+	for _, test := range testTable {
+		html := `<html><body><span id="productTitle">Product</span>` + test.html + `</body></html>`
+		product, error := ParseProductHTML([]byte(html))
+		if error != nil {
+			t.Fatal(error)
+		}
+		if product.Kind != test.kind {
+			t.Errorf("%v: Expected kind: %v Result: %v", test.name, test.kind, product.Kind)
+		}
+		if !reflect.DeepEqual(product.Media, test.media) {
+			t.Errorf("%v: Expected media: %+v Result: %+v", test.name, test.media, product.Media)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package amazonscraper
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+
+	"github.com/programmingparody/amazing-bot/scrapers"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+//Offer of a product by a seller, listed on the offer-listing page
+type Offer struct {
+	Seller    string
+	Condition string //"New", "Used - Like New", "Renewed", ...
+	Price     float32
+	Shipping  float32 //0 when shipping is free or unknown
+	Currency  string
+	Prime     bool
+}
+
+//OfferListingURL returns the page listing every offer (new, used, renewed) of a product
+func OfferListingURL(marketplace string, asin string) *url.URL {
+	URL := marketplaceURL(marketplace, "/gp/aod/ajax/")
+	URL.RawQuery = url.Values{"asin": []string{asin}}.Encode()
+	return URL
+}
+
+//ParseOffersHTML (html of the "All offers" panel, or the older offer-listing page) into Offers, in the order they're listed
+//Returns ErrCaptcha or ErrRobotCheck when Amazon didn't serve the offers
+func ParseOffersHTML(html []byte, marketplace string) ([]Offer, error) {
+	document, error := goquery.NewDocumentFromReader(bytes.NewBuffer(html))
+	if error != nil {
+		return nil, error
+	}
+	if error = checkBlocked(document); error != nil {
+		return nil, error
+	}
+	settings := MarketplaceFromTLD(marketplace)
+
+	offers := []Offer{}
+	document.Find("#aod-pinned-offer, #aod-offer, .olpOffer").Each(func(_ int, s *goquery.Selection) {
+		price, currency, found := scrapers.ParsePrice(s.Find(".olpOfferPrice, .a-price .a-offscreen").First().Text(), settings.Locale, settings.Currency)
+		if !found {
+			return
+		}
+
+		sellerElement := s.Find("#aod-offer-soldBy a, .olpSellerName a").First()
+		if sellerElement.Length() == 0 {
+			sellerElement = s.Find("#aod-offer-soldBy .a-col-right span, .olpSellerName").First()
+		}
+		seller := strings.TrimSpace(sellerElement.Text())
+		if len(seller) == 0 {
+			seller = s.Find(".olpSellerName img").AttrOr("alt", "")
+		}
+
+		shippingText := s.Find("[data-csa-c-delivery-price]").AttrOr("data-csa-c-delivery-price", "")
+		if len(shippingText) == 0 {
+			shippingText = s.Find(".olpShippingPrice").First().Text()
+		}
+		shipping, _, _ := scrapers.ParsePrice(shippingText, settings.Locale, currency)
+
+		offers = append(offers, Offer{
+			Seller:    seller,
+			Condition: strings.Join(strings.Fields(s.Find("#aod-offer-heading, .olpCondition").First().Text()), " "),
+			Price:     float32(price),
+			Shipping:  float32(shipping),
+			Currency:  currency,
+			Prime:     s.Find("i.a-icon-prime").Length() > 0,
+		})
+	})
+	return offers, nil
+}
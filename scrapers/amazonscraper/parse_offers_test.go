@@ -0,0 +1,64 @@
+package amazonscraper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseOffersHTML(t *testing.T) {
+	testTable := []struct {
+		name     string
+		html     string
+		expected []Offer
+	}{
+		{
+			name: "all offers panel",
+			html: `<div id="aod-pinned-offer">
+					<span class="a-price"><span class="a-offscreen">$49.99</span></span><i class="a-icon a-icon-prime"></i>
+					<div id="aod-offer-heading"><h5> New </h5></div>
+					<span data-csa-c-delivery-price="FREE"></span>
+					<div id="aod-offer-soldBy"><div class="a-col-right"><span>Amazon.com</span></div></div>
+				</div>
+				<div id="aod-offer-list">
+					<div id="aod-offer">
+						<span class="a-price"><span class="a-offscreen">$31.50</span></span>
+						<div id="aod-offer-heading"><h5>Used - Like New</h5></div>
+						<span data-csa-c-delivery-price="$5.99"></span>
+						<div id="aod-offer-soldBy"><div class="a-col-right"><a href="#">Warehouse Deals</a></div></div>
+					</div>
+				</div>`,
+			expected: []Offer{
+				{Seller: "Amazon.com", Condition: "New", Price: 49.99, Currency: "USD", Prime: true},
+				{Seller: "Warehouse Deals", Condition: "Used - Like New", Price: 31.5, Shipping: 5.99, Currency: "USD"},
+			},
+		},
+		{
+			name: "offer-listing page",
+			html: `<div class="a-row olpOffer">
+					<span class="a-size-large olpOfferPrice">$39.00</span>
+					<span class="olpShippingPrice">$4.49</span>
+					<span class="olpCondition"> Renewed </span>
+					<h3 class="olpSellerName"><span><a href="#">Refurb Shop</a></span></h3>
+				</div>
+				<div class="a-row olpOffer">
+					<span class="a-size-large olpOfferPrice">$45.00</span>
+					<span class="olpCondition">New</span>
+					<h3 class="olpSellerName"><img alt="Amazon.com" src="#"></h3>
+				</div>`,
+			expected: []Offer{
+				{Seller: "Refurb Shop", Condition: "Renewed", Price: 39, Shipping: 4.49, Currency: "USD"},
+				{Seller: "Amazon.com", Condition: "New", Price: 45, Currency: "USD"},
+			},
+		},
+	}
+
+	for _, test := range testTable {
+		offers, error := ParseOffersHTML([]byte(`<html><body>`+test.html+`</body></html>`), "com")
+		if error != nil {
+			t.Fatal(error)
+		}
+		if !reflect.DeepEqual(offers, test.expected) {
+			t.Errorf("%v: Expected: %+v Result: %+v", test.name, test.expected, offers)
+		}
+	}
+}
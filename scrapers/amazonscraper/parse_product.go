@@ -3,6 +3,7 @@ package amazonscraper
 import (
 	"bytes"
 	"encoding/json"
+	"regexp"
 	"strings"
 
 	"github.com/programmingparody/amazing-bot/scrapers"
@@ -12,14 +13,36 @@ import (
 
 //Product represents a scraped Amazon.com product
 type Product struct {
-	Title         string
-	Price         float32
-	ImageURL      string
-	Description   string
-	RatingsCount  uint    //Number of ratings (the amound of people giving a product a star count)
-	Rating        float32 //Rating percentage (0-5, 5 = five star rating, 4.5 would be 4 and half stars)
-	OutOfStock    bool
-	OriginalPrice float32
+	Title                 string
+	Price                 float32
+	ImageURL              string
+	Images                []Image //Every gallery image and video thumbnail
+	Description           string
+	RatingsCount          uint    //Number of ratings (the amound of people giving a product a star count)
+	Rating                float32 //Rating percentage (0-5, 5 = five star rating, 4.5 would be 4 and half stars)
+	OutOfStock            bool
+	OriginalPrice         float32
+	Currency              string //ISO 4217 code of Price and OriginalPrice
+	Brand                 string
+	Features              []string //"About this item" feature bullets
+	Seller                string   //Sold by
+	ShipsFrom             string
+	Prime                 bool
+	FreeShipping          bool
+	RatingHistogram       [5]uint //Percentage of ratings per star count. RatingHistogram[0] is 1 star, RatingHistogram[4] is 5 stars
+	TopPositiveReview     *Review
+	TopCriticalReview     *Review
+	Kind                  ProductKind
+	Media                 *Media //Book, Kindle, music and video fields. nil for KindGeneral
+	Coupon                *Coupon
+	Deal                  *Deal
+	SubscribeAndSavePrice float32           //Price when subscribed to Subscribe & Save, 0 when not offered
+	Categories            []string          //Breadcrumb category path, broadest first
+	BestSellersRanks      []SalesRank       //Every Best Sellers Rank entry, broadest category first
+	VariationDimensions   []string          //Names of the dimensions products vary by ("Size", "Color")
+	Variants              []Variant         //Every variant, including this one (Variant.Selected)
+	Sources               map[string]string //Field name -> parsing strategy (SourceHTML, SourceJSONLD, ...) that produced the value
+	SelectorVersion       string            //Version of the SelectorSet used by the SourceHTML strategy
 }
 
 func findFallback(document *goquery.Document, selectors ...string) (selection *goquery.Selection, found bool) {
@@ -35,22 +58,54 @@ func findFallback(document *goquery.Document, selectors ...string) (selection *g
 	return selection, found
 }
 
-func numbersFromStringFallback(input string, defaultValue float64) []float64 {
-	result := scrapers.NumbersFromString(input)
+func numbersFromStringFallback(input string, locale scrapers.Locale, defaultValue float64) []float64 {
+	result := scrapers.NumbersFromStringWithLocale(input, locale)
 	return append(result, defaultValue)
 }
 
 //ParseProductHTML (html) into a *Product
+//The marketplace (used for number formats and currency) is read from the page's canonical link, defaulting to amazon.com
 func ParseProductHTML(html []byte) (*Product, error) {
+	return ParseProductHTMLFromMarketplace(html, "")
+}
+
+//ParseProductHTMLFromMarketplace (html) into a *Product, reading prices and numbers the way the marketplace (TLD) writes them
+//Returns ErrCaptcha, ErrRobotCheck, ErrNotFound or ErrNotProductPage when Amazon didn't serve a product page
+func ParseProductHTMLFromMarketplace(html []byte, marketplace string) (*Product, error) {
 
 	document, error := goquery.NewDocumentFromReader(bytes.NewBuffer(html))
 	if error != nil {
 		return nil, error
 	}
 
-	//Parsing
+	if error = checkPage(document, Selectors()); error != nil {
+		return nil, error
+	}
+
+	if len(marketplace) == 0 {
+		marketplace, _, _ = ExtractASIN(document.Find(`link[rel="canonical"]`).AttrOr("href", ""))
+	}
+	settings := MarketplaceFromTLD(marketplace)
+
+	product := mergeStrategies(document, settings, []parseStrategy{
+		{Source: SourceHTML, Parse: parseProductElements},
+		{Source: SourceJSONLD, Parse: parseJSONLD},
+		{Source: SourceAState, Parse: parseAState},
+		{Source: SourceTwister, Parse: parseTwisterPriceData},
+		{Source: SourceTwister, Parse: parseTwisterVariants},
+	})
+	if len(product.Currency) == 0 {
+		product.Currency = settings.Currency
+	}
+	product.SelectorVersion = Selectors().Version
+	return product, nil
+}
 
-	productImageElement, _ := findFallback(document, `#imgTagWrapperId img:last-child`)
+//parseProductElements is the SourceHTML strategy, reading the page's elements through CSS selectors
+func parseProductElements(document *goquery.Document, settings Marketplace) *Product {
+	selectors := Selectors()
+
+	productImageElement, _ := findFallback(document, selectors.Get(SelectorImage)...)
 	productImageURL := productImageElement.AttrOr("data-old-hires", "")
 	if len(productImageURL) == 0 || productImageURL[0:5] == "data:" {
 		productImageURL = productImageElement.AttrOr("src", "")
@@ -69,10 +124,10 @@ func ParseProductHTML(html []byte) (*Product, error) {
 		}
 	}
 
-	productDescription := document.Find(`#productDescription`)
+	productDescription, _ := findFallback(document, selectors.Get(SelectorDescription)...)
 	productTitle := productImageElement.AttrOr("alt", "")
 	if len(productTitle) == 0 {
-		productElement, _ := findFallback(document, "#productTitle")
+		productElement, _ := findFallback(document, selectors.Get(SelectorTitle)...)
 		parentalAdvisoryElement := productElement.Find("#parentalAdvisory")
 		//Frick the rules, element data may come in handly
 		parentalAdvisoryElement.Remove()
@@ -80,30 +135,126 @@ func ParseProductHTML(html []byte) (*Product, error) {
 		productTitle = productElement.Text()
 	}
 
-	priceElement, _ := findFallback(document, "#price_inside_buybox", "#priceblock_ourprice", "#newBuyBoxPrice")
+	priceElement, _ := findFallback(document, selectors.Get(SelectorPrice)...)
 	priceText := priceElement.First().Text()
-	price := numbersFromStringFallback(priceText, 0)[0]
+	price, currency, priceFound := scrapers.ParsePrice(priceText, settings.Locale, settings.Currency)
+	if !priceFound {
+		currency = ""
+	}
 
-	originalPriceElement, _ := findFallback(document, "span.priceBlockStrikePriceString.a-text-strike")
-	originalPrice := float32(numbersFromStringFallback(originalPriceElement.Text(), 0)[0])
+	originalPriceElement, _ := findFallback(document, selectors.Get(SelectorOriginalPrice)...)
+	originalPrice, _, _ := scrapers.ParsePrice(originalPriceElement.Text(), settings.Locale, currency)
 
-	outOfStockElement, found := findFallback(document, `#almOutOfStockAvailability_feature_div`, `#availability > span`)
+	outOfStockElement, found := findFallback(document, selectors.Get(SelectorOutOfStock)...)
 	outOfStock := false
 	outOfStockText := strings.Trim(outOfStockElement.Text(), " \n")
 	if found && len(outOfStockText) > 0 && outOfStockText == "Currently unavailable." {
 		outOfStock = true
 	}
 
-	ratingsCountText := document.Find("#acrCustomerReviewText").First().Text()
-	ratingsText := document.Find("#acrPopover").First().AttrOr("title", "0")
+	brandElement, _ := findFallback(document, selectors.Get(SelectorBrand)...)
+	brand := cleanBrand(brandElement.First().Text())
+
+	features := []string{}
+	featureElements, _ := findFallback(document, selectors.Get(SelectorFeatures)...)
+	featureElements.Each(func(_ int, s *goquery.Selection) {
+		feature := strings.TrimSpace(s.Text())
+		if len(feature) > 0 {
+			features = append(features, feature)
+		}
+	})
+
+	seller, shipsFrom := parseSeller(document, selectors)
+
+	_, prime := findFallback(document, selectors.Get(SelectorPrime)...)
+	shippingElement, _ := findFallback(document, selectors.Get(SelectorShipping)...)
+	shippingText := strings.ToLower(shippingElement.Text())
+	freeShipping := strings.Contains(shippingText, "free shipping") || strings.Contains(shippingText, "free delivery")
+
+	topPositiveReview, topCriticalReview := parseTopReviews(document, selectors, settings)
+
+	images := parseGallery(document, selectors)
+	if len(productImageURL) == 0 && len(images) > 0 {
+		productImageURL = images[0].URL
+	}
+
+	categories := parseBreadcrumbs(document, selectors)
+	kind, media := parseMedia(document, selectors, settings, categories)
+
+	ratingsCountElement, _ := findFallback(document, selectors.Get(SelectorRatingsCount)...)
+	ratingsCountText := ratingsCountElement.First().Text()
+	ratingsElement, _ := findFallback(document, selectors.Get(SelectorRating)...)
+	ratingsText := ratingsElement.First().AttrOr("title", "0")
 	return &Product{
 		Title:         strings.Trim(productTitle, "\n "),
 		Price:         float32(price),
 		ImageURL:      productImageURL,
+		Images:        images,
 		Description:   strings.Trim(productDescription.Find("p").Text(), "\n "),
-		RatingsCount:  uint(numbersFromStringFallback(ratingsCountText, 0)[0]),
-		Rating:        float32(numbersFromStringFallback(ratingsText, 0)[0]),
+		RatingsCount:  uint(numbersFromStringFallback(ratingsCountText, settings.Locale, 0)[0]),
+		Rating:        float32(numbersFromStringFallback(ratingsText, settings.Locale, 0)[0]),
 		OutOfStock:    outOfStock,
-		OriginalPrice: originalPrice,
-	}, nil
+		OriginalPrice: float32(originalPrice),
+		Currency:      currency,
+		Brand:         brand,
+		Features:      features,
+		Seller:        seller,
+		ShipsFrom:     shipsFrom,
+		Prime:         prime,
+		FreeShipping:  freeShipping,
+
+		RatingHistogram:   parseHistogram(document, selectors),
+		TopPositiveReview: topPositiveReview,
+		TopCriticalReview: topCriticalReview,
+
+		Categories:       categories,
+		BestSellersRanks: parseBestSellersRanks(document, selectors, settings),
+
+		Kind:  kind,
+		Media: media,
+
+		Coupon:                parseCoupon(document, selectors, settings),
+		Deal:                  parseDeal(document, selectors),
+		SubscribeAndSavePrice: parseSubscribeAndSavePrice(document, selectors, settings),
+	}
+}
+
+//cleanBrand turns byline text ("Visit the Acer Store", "Brand: Acer") into the brand name ("Acer")
+func cleanBrand(byline string) string {
+	brand := strings.TrimSpace(byline)
+	brand = strings.TrimPrefix(brand, "Brand: ")
+	if strings.HasPrefix(brand, "Visit the ") && strings.HasSuffix(brand, " Store") {
+		brand = strings.TrimSuffix(strings.TrimPrefix(brand, "Visit the "), " Store")
+	}
+	return strings.TrimSpace(brand)
+}
+
+var merchantInfoRegex = regexp.MustCompile(`(?i)(?:ships from (.+?) and sold by (.+?)|ships from and sold by (.+?)|sold by (.+?) and fulfilled by (.+?))\.?$`)
+
+//parseSeller from the tabular buy box ("Ships from", "Sold by" rows) or the older #merchant-info sentence
+func parseSeller(document *goquery.Document, selectors *SelectorSet) (seller string, shipsFrom string) {
+	soldByElement, _ := findFallback(document, selectors.Get(SelectorSoldBy)...)
+	shipsFromElement, _ := findFallback(document, selectors.Get(SelectorShipsFrom)...)
+	seller = strings.TrimSpace(soldByElement.First().Text())
+	shipsFrom = strings.TrimSpace(shipsFromElement.First().Text())
+	if len(seller) > 0 {
+		return seller, shipsFrom
+	}
+
+	merchantInfoElement, _ := findFallback(document, selectors.Get(SelectorMerchantInfo)...)
+	merchantInfo := strings.Join(strings.Fields(merchantInfoElement.First().Text()), " ")
+	merchantInfo = strings.SplitN(merchantInfo, ". ", 2)[0]
+	match := merchantInfoRegex.FindStringSubmatch(merchantInfo)
+	switch {
+	case match == nil:
+		sellerProfileElement, _ := findFallback(document, selectors.Get(SelectorSellerProfile)...)
+		seller = strings.TrimSpace(sellerProfileElement.First().Text())
+	case len(match[1]) > 0:
+		shipsFrom, seller = match[1], match[2]
+	case len(match[3]) > 0:
+		shipsFrom, seller = match[3], match[3]
+	default:
+		seller, shipsFrom = match[4], match[5]
+	}
+	return strings.TrimSpace(seller), strings.TrimSpace(shipsFrom)
 }
@@ -0,0 +1,93 @@
+package amazonscraper
+
+import "testing"
+
+func TestCleanBrand(t *testing.T) {
+	testTable := []struct {
+		input    string
+		expected string
+	}{
+		{input: "Visit the Acer Store", expected: "Acer"},
+		{input: "  Visit the Acer Store\n", expected: "Acer"},
+		{input: "Brand: Acer", expected: "Acer"},
+		{input: "Acer", expected: "Acer"},
+		{input: "Visit the Store Store", expected: "Store"},
+		{input: "Visit the Acer website", expected: "Visit the Acer website"},
+		{input: "", expected: ""},
+	}
+
+	for _, test := range testTable {
+		if result := cleanBrand(test.input); result != test.expected {
+			t.Errorf("Input: %q Expected: %q Result: %q", test.input, test.expected, result)
+		}
+	}
+}
+
+func TestParseProductHTMLSeller(t *testing.T) {
+	testTable := []struct {
+		name      string
+		html      string
+		seller    string
+		shipsFrom string
+		prime     bool
+	}{
+		{
+			name: "tabular buy box",
+			html: `<span class="tabular-buybox-text" tabular-attribute-name="Ships from">Amazon</span>
+				<span class="tabular-buybox-text" tabular-attribute-name="Sold by">Acer Direct</span>`,
+			seller:    "Acer Direct",
+			shipsFrom: "Amazon",
+		},
+		{
+			name:      "ships from and sold by the same merchant",
+			html:      `<div id="merchant-info"> Ships from and sold by Amazon.com. </div>`,
+			seller:    "Amazon.com",
+			shipsFrom: "Amazon.com",
+		},
+		{
+			name:      "ships from one merchant, sold by another",
+			html:      `<div id="merchant-info">Ships from Amazon and sold by Acer Direct.</div>`,
+			seller:    "Acer Direct",
+			shipsFrom: "Amazon",
+		},
+		{
+			name:      "sold by and fulfilled by",
+			html:      `<div id="merchant-info">Sold by Acer Direct and Fulfilled by Amazon. Gift-wrap available.</div>`,
+			seller:    "Acer Direct",
+			shipsFrom: "Amazon",
+		},
+		{
+			name:   "seller profile link only",
+			html:   `<div id="merchant-info">Available from these sellers.</div><a id="sellerProfileTriggerId">Acer Direct</a>`,
+			seller: "Acer Direct",
+		},
+		{
+			name:  "prime badge",
+			html:  `<i id="prime-badge"></i>`,
+			prime: true,
+		},
+		{
+			name:  "prime icon in the buy box",
+			html:  `<div id="buybox"><i class="a-icon a-icon-prime"></i></div>`,
+			prime: true,
+		},
+		{
+			name: "prime icon outside the buy box",
+			html: `<div id="similar-items"><i class="a-icon a-icon-prime"></i></div>`,
+		},
+	}
+
+	for _, test := range testTable {
+		html := `<html><body><span id="productTitle">Monitor</span><span id="priceblock_ourprice">$99.99</span>` + test.html + `</body></html>`
+		product, error := ParseProductHTML([]byte(html))
+		if error != nil {
+			t.Fatal(error)
+		}
+		if product.Seller != test.seller || product.ShipsFrom != test.shipsFrom {
+			t.Errorf("%v: Expected seller: %q ships from: %q Result: %q %q", test.name, test.seller, test.shipsFrom, product.Seller, product.ShipsFrom)
+		}
+		if product.Prime != test.prime {
+			t.Errorf("%v: Expected Prime: %v Result: %v", test.name, test.prime, product.Prime)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package amazonscraper
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+//Review snippet shown on a product page
+type Review struct {
+	Author string
+	Title  string
+	Body   string
+	Rating float32
+}
+
+var histogramStarsRegex = regexp.MustCompile(`([1-5])\s*star`)
+var histogramPercentRegex = regexp.MustCompile(`(\d{1,3})\s*(?:%|percent)`)
+
+//parseHistogram reads the percentage of ratings per star count. histogram[0] is 1 star, histogram[4] is 5 stars
+func parseHistogram(document *goquery.Document, selectors *SelectorSet) (histogram [5]uint) {
+	rows, _ := findFallback(document, selectors.Get(SelectorHistogramRows)...)
+	rows.Each(func(_ int, row *goquery.Selection) {
+		text := strings.Join([]string{
+			row.AttrOr("aria-label", ""),
+			row.Find("[aria-label]").AttrOr("aria-label", ""),
+			row.Find("[title]").AttrOr("title", ""),
+			row.Text(),
+		}, " ")
+		text = strings.Join(strings.Fields(text), " ")
+
+		starsMatch := histogramStarsRegex.FindStringSubmatch(text)
+		percentMatch := histogramPercentRegex.FindStringSubmatch(text)
+		if starsMatch == nil || percentMatch == nil {
+			return
+		}
+		stars, _ := strconv.Atoi(starsMatch[1])
+		percent, _ := strconv.Atoi(percentMatch[1])
+		if histogram[stars-1] == 0 {
+			histogram[stars-1] = uint(percent)
+		}
+	})
+	return histogram
+}
+
+//parseTopReviews returns the top positive and top critical reviews
+//Pages without dedicated top positive / critical blocks use the first positive (4+ stars) and critical (3- stars) reviews listed
+func parseTopReviews(document *goquery.Document, selectors *SelectorSet, settings Marketplace) (positive *Review, critical *Review) {
+	if element, found := findFallback(document, selectors.Get(SelectorPositiveReview)...); found {
+		positive = parseReview(element.First(), settings)
+	}
+	if element, found := findFallback(document, selectors.Get(SelectorCriticalReview)...); found {
+		critical = parseReview(element.First(), settings)
+	}
+
+	reviews, _ := findFallback(document, selectors.Get(SelectorReviews)...)
+	reviews.Each(func(_ int, element *goquery.Selection) {
+		review := parseReview(element, settings)
+		if review == nil {
+			return
+		}
+		if positive == nil && review.Rating >= 4 {
+			positive = review
+		}
+		if critical == nil && review.Rating > 0 && review.Rating <= 3 {
+			critical = review
+		}
+	})
+	return positive, critical
+}
+
+func parseReview(element *goquery.Selection, settings Marketplace) *Review {
+	ratingText := element.Find(`[data-hook="review-star-rating"] .a-icon-alt, [data-hook="cmps-review-star-rating"] .a-icon-alt, i.a-icon-star .a-icon-alt`).First().Text()
+	//Titles of some layouts start with the rating ("5.0 out of 5 stars Great!"), which is in its own span
+	titleElement := element.Find(`[data-hook="review-title"]`).First()
+	title := titleElement.Children().Last().Text()
+	if len(strings.TrimSpace(title)) == 0 {
+		title = titleElement.Text()
+	}
+
+	review := &Review{
+		Author: strings.TrimSpace(element.Find(".a-profile-name").First().Text()),
+		Title:  strings.TrimSpace(title),
+		Body:   strings.TrimSpace(element.Find(`[data-hook="review-body"], [data-hook="review-collapsed"]`).First().Text()),
+		Rating: float32(numbersFromStringFallback(ratingText, settings.Locale, 0)[0]),
+	}
+	if len(review.Title) == 0 && len(review.Body) == 0 {
+		return nil
+	}
+	return review
+}
@@ -0,0 +1,51 @@
+package amazonscraper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseProductHTMLReviews(t *testing.T) {
+	html := `<html><body>
+	<span id="productTitle">Thing</span>
+	<table id="histogramTable">
+		<tr><td><a title="5 stars represent 78% of rating">5 star</a></td><td>78%</td></tr>
+		<tr><td><a title="4 stars represent 12% of rating">4 star</a></td><td>12%</td></tr>
+		<tr><td>3 star</td><td>4%</td></tr>
+		<tr><td>2 star</td><td>2%</td></tr>
+		<tr><td>1 star</td><td>4%</td></tr>
+	</table>
+	<div id="cm-cr-dp-review-list">
+		<div data-hook="review">
+			<span class="a-profile-name">Sam</span>
+			<i data-hook="review-star-rating"><span class="a-icon-alt">2.0 out of 5 stars</span></i>
+			<a data-hook="review-title"><span>Broke</span></a>
+			<span data-hook="review-body"> Stopped working after a week. </span>
+		</div>
+		<div data-hook="review">
+			<span class="a-profile-name">Alex</span>
+			<i data-hook="review-star-rating"><span class="a-icon-alt">5.0 out of 5 stars</span></i>
+			<a data-hook="review-title"><span class="a-icon-alt">5.0 out of 5 stars</span><span>Great!</span></a>
+			<span data-hook="review-body">Does the job.</span>
+		</div>
+	</div>
+	</body></html>`
+
+	product, error := ParseProductHTML([]byte(html))
+	if error != nil {
+		t.Fatal(error)
+	}
+
+	expectedHistogram := [5]uint{4, 2, 4, 12, 78}
+	if product.RatingHistogram != expectedHistogram {
+		t.Errorf("Expected: %v Result: %v", expectedHistogram, product.RatingHistogram)
+	}
+	expectedPositive := &Review{Author: "Alex", Title: "Great!", Body: "Does the job.", Rating: 5}
+	if !reflect.DeepEqual(product.TopPositiveReview, expectedPositive) {
+		t.Errorf("Expected: %+v Result: %+v", expectedPositive, product.TopPositiveReview)
+	}
+	expectedCritical := &Review{Author: "Sam", Title: "Broke", Body: "Stopped working after a week.", Rating: 2}
+	if !reflect.DeepEqual(product.TopCriticalReview, expectedCritical) {
+		t.Errorf("Expected: %+v Result: %+v", expectedCritical, product.TopCriticalReview)
+	}
+}
@@ -0,0 +1,69 @@
+package amazonscraper
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+
+	"github.com/programmingparody/amazing-bot/scrapers"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+//SearchResult is a lightweight product listed on a search results page
+type SearchResult struct {
+	ASIN         string
+	Title        string
+	Price        float32
+	Currency     string
+	Rating       float32
+	RatingsCount uint
+	ThumbnailURL string
+}
+
+//SearchURL returns the search results page of the keywords on a marketplace (TLD)
+func SearchURL(marketplace string, keywords string) *url.URL {
+	URL := marketplaceURL(marketplace, "/s")
+	URL.RawQuery = url.Values{"k": []string{keywords}}.Encode()
+	return URL
+}
+
+//ParseSearchHTML (html of a search results page) into SearchResults, in the order they're listed
+//Returns ErrCaptcha or ErrRobotCheck when Amazon didn't serve the search results
+func ParseSearchHTML(html []byte, marketplace string) ([]SearchResult, error) {
+	document, error := goquery.NewDocumentFromReader(bytes.NewBuffer(html))
+	if error != nil {
+		return nil, error
+	}
+	if error = checkBlocked(document); error != nil {
+		return nil, error
+	}
+	settings := MarketplaceFromTLD(marketplace)
+
+	results := []SearchResult{}
+	document.Find(`div[data-component-type="s-search-result"][data-asin]`).Each(func(_ int, s *goquery.Selection) {
+		asin := strings.TrimSpace(s.AttrOr("data-asin", ""))
+		title := strings.TrimSpace(s.Find("h2").First().Text())
+		if len(asin) == 0 || len(title) == 0 {
+			return
+		}
+
+		price, currency, priceFound := scrapers.ParsePrice(s.Find(".a-price .a-offscreen").First().Text(), settings.Locale, settings.Currency)
+		if !priceFound {
+			currency = settings.Currency
+		}
+		ratingText := s.Find("i.a-icon-star-small .a-icon-alt, i.a-icon-star .a-icon-alt").First().Text()
+		ratingsCountText := s.Find(`span[aria-label] a[href*="customerReviews"], a[href*="#customerReviews"] span`).First().Text()
+
+		results = append(results, SearchResult{
+			ASIN:         asin,
+			Title:        title,
+			Price:        float32(price),
+			Currency:     currency,
+			Rating:       float32(numbersFromStringFallback(ratingText, settings.Locale, 0)[0]),
+			RatingsCount: uint(numbersFromStringFallback(ratingsCountText, settings.Locale, 0)[0]),
+			ThumbnailURL: s.Find("img.s-image").First().AttrOr("src", ""),
+		})
+	})
+	return results, nil
+}
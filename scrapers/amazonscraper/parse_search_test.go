@@ -0,0 +1,56 @@
+package amazonscraper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSearchHTML(t *testing.T) {
+	html := `<html><body>
+	<div data-component-type="s-search-result" data-asin="B07MPCSHQD">
+		<img class="s-image" src="https://m.media-amazon.com/images/I/1.jpg">
+		<h2><a href="/Keyboard/dp/B07MPCSHQD"><span>Mechanical Keyboard</span></a></h2>
+		<i class="a-icon a-icon-star-small"><span class="a-icon-alt">4.6 out of 5 stars</span></i>
+		<span aria-label="1,234"><a href="/Keyboard/dp/B07MPCSHQD#customerReviews"><span>1,234</span></a></span>
+		<span class="a-price"><span class="a-offscreen">$49.99</span></span>
+	</div>
+	<div data-component-type="s-search-result" data-asin="">
+		<h2>Not a product</h2>
+	</div>
+	<div data-component-type="s-search-result" data-asin="B0148NNKTC">
+		<h2><a href="/Monitor/dp/B0148NNKTC"><span>Monitor</span></a></h2>
+	</div>
+	</body></html>`
+
+	results, error := ParseSearchHTML([]byte(html), "com")
+	if error != nil {
+		t.Fatal(error)
+	}
+	expected := []SearchResult{
+		{
+			ASIN:         "B07MPCSHQD",
+			Title:        "Mechanical Keyboard",
+			Price:        49.99,
+			Currency:     "USD",
+			Rating:       4.6,
+			RatingsCount: 1234,
+			ThumbnailURL: "https://m.media-amazon.com/images/I/1.jpg",
+		},
+		{
+			ASIN:     "B0148NNKTC",
+			Title:    "Monitor",
+			Currency: "USD",
+		},
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Expected: %+v Result: %+v", expected, results)
+	}
+}
+
+func TestSearchURL(t *testing.T) {
+	result := SearchURL("co.uk", "mechanical keyboard").String()
+	expected := "https://www.amazon.co.uk/s?k=mechanical+keyboard"
+	if result != expected {
+		t.Errorf("Expected: %v Result: %v", expected, result)
+	}
+}
@@ -0,0 +1,224 @@
+package amazonscraper
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/programmingparody/amazing-bot/scrapers"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+//parseJSONLD is the SourceJSONLD strategy, reading schema.org Product structured data
+func parseJSONLD(document *goquery.Document, settings Marketplace) *Product {
+	var product map[string]interface{}
+	document.Find(`script[type="application/ld+json"]`).EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		var data interface{}
+		if json.Unmarshal([]byte(s.Text()), &data) != nil {
+			return true
+		}
+		product = findJSONLDProduct(data)
+		return product == nil
+	})
+	if product == nil {
+		return nil
+	}
+
+	result := &Product{
+		Title:       strings.TrimSpace(jsonString(product["name"])),
+		Description: strings.TrimSpace(jsonString(product["description"])),
+		ImageURL:    jsonFirstString(product["image"], "url"),
+		Brand:       jsonFirstString(product["brand"], "name"),
+	}
+
+	if rating, ok := product["aggregateRating"].(map[string]interface{}); ok {
+		ratingValue, _ := strconv.ParseFloat(jsonString(rating["ratingValue"]), 64)
+		result.Rating = float32(ratingValue)
+		ratingsCount, _ := strconv.ParseFloat(jsonString(rating["reviewCount"]), 64)
+		if ratingsCount == 0 {
+			ratingsCount, _ = strconv.ParseFloat(jsonString(rating["ratingCount"]), 64)
+		}
+		result.RatingsCount = uint(ratingsCount)
+	}
+
+	offers := product["offers"]
+	if list, ok := offers.([]interface{}); ok && len(list) > 0 {
+		offers = list[0]
+	}
+	if offer, ok := offers.(map[string]interface{}); ok {
+		priceText := jsonString(offer["price"])
+		if len(priceText) == 0 {
+			priceText = jsonString(offer["lowPrice"])
+		}
+		if price, error := strconv.ParseFloat(priceText, 64); error == nil {
+			result.Price = float32(price)
+			result.Currency = scrapers.DetectCurrency(jsonString(offer["priceCurrency"]), settings.Currency)
+		}
+		result.OutOfStock = strings.HasSuffix(jsonString(offer["availability"]), "OutOfStock")
+	}
+	return result
+}
+
+//findJSONLDProduct finds the first object with "@type": "Product" in JSON-LD data (object, array or @graph)
+func findJSONLDProduct(data interface{}) map[string]interface{} {
+	switch value := data.(type) {
+	case []interface{}:
+		for _, item := range value {
+			if product := findJSONLDProduct(item); product != nil {
+				return product
+			}
+		}
+	case map[string]interface{}:
+		if jsonString(value["@type"]) == "Product" {
+			return value
+		}
+		if types, ok := value["@type"].([]interface{}); ok {
+			for _, t := range types {
+				if jsonString(t) == "Product" {
+					return value
+				}
+			}
+		}
+		return findJSONLDProduct(value["@graph"])
+	}
+	return nil
+}
+
+//parseAState is the SourceAState strategy, reading the price from <script type="a-state"> JSON blobs
+//Only blobs about the page's own ASIN are read, the others are carousels and sponsored products
+func parseAState(document *goquery.Document, settings Marketplace) *Product {
+	asin := pageASIN(document)
+	if len(asin) == 0 {
+		return nil
+	}
+	var result *Product
+	document.Find(`script[type="a-state"]`).EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		var data interface{}
+		if json.Unmarshal([]byte(s.Text()), &data) != nil || !strings.EqualFold(findASIN(data), asin) {
+			return true
+		}
+		result = productFromPriceData(data, settings)
+		return result == nil
+	})
+	return result
+}
+
+//pageASIN is the ASIN of the product the page is about, empty when the page doesn't say
+func pageASIN(document *goquery.Document) string {
+	if asin := strings.TrimSpace(document.Find("input#ASIN").AttrOr("value", "")); len(asin) > 0 {
+		return asin
+	}
+	_, asin, _ := ExtractASIN(document.Find(`link[rel="canonical"]`).AttrOr("href", ""))
+	return asin
+}
+
+//findASIN returns the first "asin" value in JSON data
+func findASIN(data interface{}) string {
+	switch value := data.(type) {
+	case []interface{}:
+		for _, item := range value {
+			if asin := findASIN(item); len(asin) > 0 {
+				return asin
+			}
+		}
+	case map[string]interface{}:
+		for key, item := range value {
+			if asin, ok := item.(string); ok && strings.EqualFold(key, "asin") {
+				return asin
+			}
+		}
+		keys := []string{}
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if asin := findASIN(value[key]); len(asin) > 0 {
+				return asin
+			}
+		}
+	}
+	return ""
+}
+
+//parseTwisterPriceData is the SourceTwister strategy, reading the buying options price JSON
+func parseTwisterPriceData(document *goquery.Document, settings Marketplace) *Product {
+	var data interface{}
+	if json.Unmarshal([]byte(document.Find(".twister-plus-buying-options-price-data").First().Text()), &data) == nil {
+		if result := productFromPriceData(data, settings); result != nil {
+			return result
+		}
+	}
+
+	priceText := document.Find("#twister-plus-price-data-price").AttrOr("value", "")
+	price, error := strconv.ParseFloat(priceText, 64)
+	if error != nil {
+		return nil
+	}
+	currencyText := document.Find("#twister-plus-price-data-price-unit").AttrOr("value", "")
+	return &Product{
+		Price:    float32(price),
+		Currency: scrapers.DetectCurrency(currencyText, settings.Currency),
+	}
+}
+
+//productFromPriceData finds the first {"priceAmount": ...} object in price JSON
+func productFromPriceData(data interface{}, settings Marketplace) *Product {
+	switch value := data.(type) {
+	case []interface{}:
+		for _, item := range value {
+			if product := productFromPriceData(item, settings); product != nil {
+				return product
+			}
+		}
+	case map[string]interface{}:
+		if price, ok := value["priceAmount"].(float64); ok {
+			currencyText := jsonString(value["currencyCode"]) + jsonString(value["currencySymbol"]) + jsonString(value["displayPrice"])
+			return &Product{
+				Price:    float32(price),
+				Currency: scrapers.DetectCurrency(currencyText, settings.Currency),
+			}
+		}
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if product := productFromPriceData(value[key], settings); product != nil {
+				return product
+			}
+		}
+	}
+	return nil
+}
+
+//jsonString returns strings and numbers as a string ("" for anything else)
+func jsonString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		return v.String()
+	}
+	return ""
+}
+
+//jsonFirstString returns a string, the first string of an array, or the key of an object
+func jsonFirstString(value interface{}, key string) string {
+	switch v := value.(type) {
+	case []interface{}:
+		if len(v) > 0 {
+			return jsonFirstString(v[0], key)
+		}
+	case map[string]interface{}:
+		return jsonString(v[key])
+	case string:
+		return v
+	}
+	return ""
+}
@@ -0,0 +1,113 @@
+package amazonscraper
+
+import "testing"
+
+func TestParseProductHTMLStructuredFallback(t *testing.T) {
+	html := `<html><head>
+	<link rel="canonical" href="https://www.amazon.de/dp/B0148NNKTC">
+	<script type="application/ld+json">
+	{"@context": "https://schema.org", "@graph": [
+		{"@type": "BreadcrumbList"},
+		{
+			"@type": "Product",
+			"name": "JSON-LD Title",
+			"image": ["https://m.media-amazon.com/images/I/1.jpg", "https://m.media-amazon.com/images/I/2.jpg"],
+			"brand": {"@type": "Brand", "name": "Acer"},
+			"aggregateRating": {"ratingValue": "4.5", "reviewCount": 1653},
+			"offers": {"price": "1299.99", "priceCurrency": "EUR", "availability": "https://schema.org/InStock"}
+		}
+	]}
+	</script>
+	</head><body>
+	<span id="productTitle">HTML Title</span>
+	<div class="twister-plus-buying-options-price-data">{"desktop_buybox_group_1":[{"displayPrice":"1.199,99 €","priceAmount":1199.99,"currencySymbol":"€"}]}</div>
+	</body></html>`
+
+	product, error := ParseProductHTML([]byte(html))
+	if error != nil {
+		t.Fatal(error)
+	}
+
+	expected := []struct {
+		field  string
+		value  interface{}
+		source string
+	}{
+		{"Title", "HTML Title", SourceHTML},
+		{"Brand", "Acer", SourceJSONLD},
+		{"ImageURL", "https://m.media-amazon.com/images/I/1.jpg", SourceJSONLD},
+		{"Price", float32(1299.99), SourceJSONLD},
+		{"Currency", "EUR", SourceJSONLD},
+		{"Rating", float32(4.5), SourceJSONLD},
+		{"RatingsCount", uint(1653), SourceJSONLD},
+	}
+	values := map[string]interface{}{
+		"Title":        product.Title,
+		"Brand":        product.Brand,
+		"ImageURL":     product.ImageURL,
+		"Price":        product.Price,
+		"Currency":     product.Currency,
+		"Rating":       product.Rating,
+		"RatingsCount": product.RatingsCount,
+	}
+	for _, e := range expected {
+		if values[e.field] != e.value || product.Sources[e.field] != e.source {
+			t.Errorf("%v: Expected: %v (%v) Result: %v (%v)", e.field, e.value, e.source, values[e.field], product.Sources[e.field])
+		}
+	}
+}
+
+func TestParseProductHTMLTwisterFallback(t *testing.T) {
+	html := `<html><body>
+	<span id="productTitle">HTML Title</span>
+	<div class="twister-plus-buying-options-price-data">{"desktop_buybox_group_1":[{"displayPrice":"£19.99","priceAmount":19.99,"currencySymbol":"£"}]}</div>
+	</body></html>`
+
+	product, error := ParseProductHTML([]byte(html))
+	if error != nil {
+		t.Fatal(error)
+	}
+	if product.Price != 19.99 || product.Currency != "GBP" || product.Sources["Price"] != SourceTwister {
+		t.Errorf("Expected: 19.99 GBP (%v) Result: %v %v (%v)", SourceTwister, product.Price, product.Currency, product.Sources["Price"])
+	}
+}
+
+func TestParseProductHTMLPriceFromOneSource(t *testing.T) {
+	html := `<html><head>
+	<script type="application/ld+json">
+	{"@type": "Product", "name": "JSON-LD Title", "offers": {"price": "1299.99", "priceCurrency": "EUR"}}
+	</script>
+	</head><body>
+	<span id="productTitle">HTML Title</span>
+	<span class="priceBlockStrikePriceString a-text-strike">$1,499.99</span>
+	</body></html>`
+
+	product, error := ParseProductHTML([]byte(html))
+	if error != nil {
+		t.Fatal(error)
+	}
+	if product.Price != 1299.99 || product.Currency != "EUR" || product.OriginalPrice != 0 {
+		t.Errorf("Expected: 1299.99 EUR without an original price Result: %v %v %v", product.Price, product.Currency, product.OriginalPrice)
+	}
+	if _, found := product.Sources["OriginalPrice"]; found {
+		t.Errorf("Expected no source for OriginalPrice, got %v", product.Sources["OriginalPrice"])
+	}
+}
+
+func TestParseProductHTMLAStateOfThePageASIN(t *testing.T) {
+	html := `<html><head>
+	<link rel="canonical" href="https://www.amazon.com/dp/B000000001">
+	</head><body>
+	<span id="productTitle">HTML Title</span>
+	<script type="a-state" data-a-state='{"key":"sponsored-carousel"}'>{"asin":"B000000009","price":{"priceAmount":5.99,"currencySymbol":"$"}}</script>
+	<script type="a-state" data-a-state='{"key":"desktop-buybox"}'>{"asin":"B000000001","price":{"priceAmount":24.99,"currencySymbol":"$"}}</script>
+	</body></html>`
+
+	product, error := ParseProductHTML([]byte(html))
+	if error != nil {
+		t.Fatal(error)
+	}
+	if product.Price != 24.99 || product.Sources["Price"] != SourceAState {
+		t.Errorf("Expected: 24.99 (%v) Result: %v (%v)", SourceAState, product.Price, product.Sources["Price"])
+	}
+}
@@ -0,0 +1,142 @@
+package amazonscraper
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/programmingparody/amazing-bot/scrapers"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+//Variant of a product (a size, colour, configuration, ...) with its own ASIN
+type Variant struct {
+	ASIN        string
+	Values      []string //One value per Product.VariationDimensions ("Large", "Black")
+	Price       float32  //0 when the page doesn't list it
+	Unavailable bool     //The page marks this variant as unavailable
+	Selected    bool     //The variant the page is showing
+}
+
+//parseTwisterVariants is a SourceTwister strategy, reading the variation dimensions and variants from the twister data
+func parseTwisterVariants(document *goquery.Document, settings Marketplace) *Product {
+	var valuesByASIN map[string][]string
+	var dimensions []string
+	var labels map[string]string
+	var valuesByDimension map[string][]string
+	var currentASIN string
+
+	document.Find("script").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		script := s.Text()
+		if !strings.Contains(script, `"dimensionValuesDisplayData"`) {
+			return true
+		}
+		scriptValue(script, "dimensionValuesDisplayData", &valuesByASIN)
+		scriptValue(script, "dimensions", &dimensions)
+		scriptValue(script, "variationDisplayLabels", &labels)
+		scriptValue(script, "variationValues", &valuesByDimension)
+		scriptValue(script, "currentAsin", &currentASIN)
+		return false
+	})
+	if len(valuesByASIN) == 0 {
+		return nil
+	}
+
+	dimensionNames := []string{}
+	for _, dimension := range dimensions {
+		name := labels[dimension]
+		if len(name) == 0 {
+			name = dimension
+		}
+		dimensionNames = append(dimensionNames, name)
+	}
+
+	swatches := parseSwatches(document, settings)
+	variants := []Variant{}
+	for asin, values := range valuesByASIN {
+		variant := swatches[asin]
+		variant.ASIN = asin
+		variant.Values = values
+		variant.Selected = asin == currentASIN
+		variants = append(variants, variant)
+	}
+	sortVariants(variants, dimensions, valuesByDimension)
+
+	return &Product{
+		VariationDimensions: dimensionNames,
+		Variants:            variants,
+	}
+}
+
+//sortVariants in the order the page lists each dimension's values, comparing the first dimension first
+//Values the page doesn't list go last, in alphabetical order
+func sortVariants(variants []Variant, dimensions []string, valuesByDimension map[string][]string) {
+	position := func(dimension int, value string) int {
+		if dimension >= len(dimensions) {
+			return 0
+		}
+		pageValues := valuesByDimension[dimensions[dimension]]
+		for i, v := range pageValues {
+			if v == value {
+				return i
+			}
+		}
+		return len(pageValues)
+	}
+	sort.SliceStable(variants, func(i, j int) bool {
+		for d := 0; d < len(variants[i].Values) && d < len(variants[j].Values); d++ {
+			a, b := variants[i].Values[d], variants[j].Values[d]
+			if a == b {
+				continue
+			}
+			if positionA, positionB := position(d, a), position(d, b); positionA != positionB {
+				return positionA < positionB
+			}
+			return a < b
+		}
+		return len(variants[i].Values) < len(variants[j].Values)
+	})
+}
+
+//parseSwatches reads prices and availability shown on the twister's swatches (<li data-defaultasin="...">)
+func parseSwatches(document *goquery.Document, settings Marketplace) map[string]Variant {
+	swatches := make(map[string]Variant)
+	document.Find("#twister li[data-defaultasin], #twister option[value]").Each(func(_ int, s *goquery.Selection) {
+		asin := s.AttrOr("data-defaultasin", "")
+		if len(asin) == 0 {
+			//Dropdown options have a "index,ASIN" value
+			parts := strings.Split(s.AttrOr("value", ""), ",")
+			asin = parts[len(parts)-1]
+		}
+		if !asinRegex.MatchString(asin) {
+			return
+		}
+		price, _, _ := scrapers.ParsePrice(s.Find(".twisterSwatchPrice, .a-size-mini").First().Text(), settings.Locale, settings.Currency)
+		swatches[asin] = Variant{
+			Price:       float32(price),
+			Unavailable: s.HasClass("swatchUnavailable") || s.HasClass("dropdownUnavailable"),
+		}
+	})
+	return swatches
+}
+
+var asinRegex = regexp.MustCompile(`^[A-Z0-9]{10}$`)
+
+//scriptValue decodes the JSON value following "key": (or 'key':) in a script into value
+func scriptValue(script string, key string, value interface{}) bool {
+	index := strings.Index(script, `"`+key+`"`)
+	if index < 0 {
+		index = strings.Index(script, `'`+key+`'`)
+	}
+	if index < 0 {
+		return false
+	}
+	rest := script[index+len(key)+2:]
+	colon := strings.Index(rest, ":")
+	if colon < 0 {
+		return false
+	}
+	return json.NewDecoder(strings.NewReader(rest[colon+1:])).Decode(value) == nil
+}
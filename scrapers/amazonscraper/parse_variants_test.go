@@ -0,0 +1,52 @@
+package amazonscraper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseProductHTMLVariants(t *testing.T) {
+	html := `<html><body>
+	<span id="productTitle">Shirt</span>
+	<div id="twister">
+		<ul>
+			<li data-defaultasin="B000000001"><span class="twisterSwatchPrice">$10.99</span></li>
+			<li data-defaultasin="B000000002" class="swatchUnavailable"><span class="twisterSwatchPrice">$11.99</span></li>
+		</ul>
+		<select><option value="-1">Select</option><option value="2,B000000003">Large</option></select>
+	</div>
+	<script type="text/javascript">
+	P.register('twister-js-init-dpx-data', function() {
+		var dataToReturn = {
+			"currentAsin" : "B000000001",
+			"dimensions" : ["size_name","color_name"],
+			"variationDisplayLabels" : {"size_name":"Size","color_name":"Color"},
+			"variationValues" : {"size_name":["Small","Medium","Large"],"color_name":["Black"]},
+			"dimensionValuesDisplayData" : {"B000000001":["Small","Black"],"B000000002":["Medium","Black"],"B000000003":["Large","Black"]},
+		};
+		return dataToReturn;
+	});
+	</script>
+	</body></html>`
+
+	product, error := ParseProductHTML([]byte(html))
+	if error != nil {
+		t.Fatal(error)
+	}
+
+	expectedDimensions := []string{"Size", "Color"}
+	if !reflect.DeepEqual(product.VariationDimensions, expectedDimensions) {
+		t.Errorf("Expected: %v Result: %v", expectedDimensions, product.VariationDimensions)
+	}
+	expectedVariants := []Variant{
+		{ASIN: "B000000001", Values: []string{"Small", "Black"}, Price: 10.99, Selected: true},
+		{ASIN: "B000000002", Values: []string{"Medium", "Black"}, Price: 11.99, Unavailable: true},
+		{ASIN: "B000000003", Values: []string{"Large", "Black"}},
+	}
+	if !reflect.DeepEqual(product.Variants, expectedVariants) {
+		t.Errorf("Expected: %+v Result: %+v", expectedVariants, product.Variants)
+	}
+	if product.Sources["Variants"] != SourceTwister {
+		t.Errorf("Expected source: %v Result: %v", SourceTwister, product.Sources["Variants"])
+	}
+}
@@ -0,0 +1,256 @@
+package amazonscraper
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/programmingparody/amazing-bot/chatapp"
+	"github.com/programmingparody/amazing-bot/scrapers"
+)
+
+//Scraper implements scrapers.Scraper for Amazon
+type Scraper struct{}
+
+//ExtractLinks implements scrapers.Scraper
+func (Scraper) ExtractLinks(s string) []string {
+	return ExtractManyProductLinkFromString(s)
+}
+
+//IsStoreURL implements scrapers.Scraper. True for the hosts of every known marketplace
+func (Scraper) IsStoreURL(url *url.URL) bool {
+	_, found := hostMarketplace(url.Hostname())
+	return found && (url.Scheme == "https" || url.Scheme == "http")
+}
+
+//IsProductLink implements scrapers.Scraper
+func (Scraper) IsProductLink(s string) bool {
+	return IsProductLink(s)
+}
+
+//IsShortLink implements scrapers.Scraper (amzn.to, a.co, ...)
+func (Scraper) IsShortLink(url *url.URL) bool {
+	return IsShortLink(url.String())
+}
+
+//ProductKey implements scrapers.Scraper. Keys are (marketplace, ASIN)
+func (Scraper) ProductKey(url *url.URL) (scrapers.ProductKey, error) {
+	marketplace, asin, found := ExtractASIN(url.String())
+	if !found {
+		return scrapers.ProductKey{}, fmt.Errorf("[AmazonScraper] ASIN not found: %s", url)
+	}
+	return scrapers.ProductKey{Marketplace: marketplace, ID: asin}, nil
+}
+
+//CanonicalURL implements scrapers.Scraper
+func (Scraper) CanonicalURL(key scrapers.ProductKey) *url.URL {
+	return CanonicalProductURL(key.Marketplace, key.ID)
+}
+
+//ParseProductHTML implements scrapers.Scraper. The result is a *Product
+func (Scraper) ParseProductHTML(url *url.URL, html []byte) (interface{}, error) {
+	marketplace, _, _ := ExtractASIN(url.String())
+	return ParseProductHTMLFromMarketplace(html, marketplace)
+}
+
+//ToChatAppProduct implements scrapers.Scraper
+func (Scraper) ToChatAppProduct(url *url.URL, product interface{}) (chatapp.Product, error) {
+	p, ok := product.(*Product)
+	if !ok || p == nil {
+		return chatapp.Product{URL: url}, fmt.Errorf("[AmazonScraper] Not an Amazon product: %v", product)
+	}
+	return ToChatAppProduct(url, p), nil
+}
+
+//Locale implements scrapers.Localizer
+func (Scraper) Locale(url *url.URL) scrapers.Locale {
+	return MarketplaceFromTLD(marketplaceOfHost(url)).Locale
+}
+
+//SearchURL implements scrapers.Searcher
+func (Scraper) SearchURL(marketplace string, keywords string) *url.URL {
+	return SearchURL(marketplace, keywords)
+}
+
+//ParseSearchHTML implements scrapers.Searcher
+func (Scraper) ParseSearchHTML(url *url.URL, html []byte) ([]chatapp.Product, error) {
+	marketplace := marketplaceOfHost(url)
+	results, error := ParseSearchHTML(html, marketplace)
+	if error != nil {
+		return nil, error
+	}
+
+	products := []chatapp.Product{}
+	for _, r := range results {
+		products = append(products, chatapp.Product{
+			Title:        r.Title,
+			Price:        r.Price,
+			Currency:     r.Currency,
+			Rating:       r.Rating,
+			RatingsCount: r.RatingsCount,
+			ImageURL:     r.ThumbnailURL,
+			URL:          CanonicalProductURL(marketplace, r.ASIN),
+		})
+	}
+	return products, nil
+}
+
+//OfferListingURL implements scrapers.OfferScraper
+func (Scraper) OfferListingURL(key scrapers.ProductKey) *url.URL {
+	return OfferListingURL(key.Marketplace, key.ID)
+}
+
+//ParseOffersHTML implements scrapers.OfferScraper
+func (Scraper) ParseOffersHTML(url *url.URL, html []byte) ([]chatapp.Offer, error) {
+	marketplace := marketplaceOfHost(url)
+	offers, error := ParseOffersHTML(html, marketplace)
+	if error != nil {
+		return nil, error
+	}
+
+	result := []chatapp.Offer{}
+	for _, o := range offers {
+		result = append(result, chatapp.Offer{
+			Seller:    o.Seller,
+			Condition: o.Condition,
+			Price:     o.Price,
+			Shipping:  o.Shipping,
+			Prime:     o.Prime,
+		})
+	}
+	return result, nil
+}
+
+//marketplaceOfHost returns the marketplace (TLD) of any Amazon URL, defaulting to DefaultMarketplace
+func marketplaceOfHost(url *url.URL) string {
+	if marketplace, found := hostMarketplace(url.Hostname()); found {
+		return marketplace
+	}
+	return DefaultMarketplace
+}
+
+//ToChatAppProduct converts a scraped Amazon Product into a chatapp.Product
+func ToChatAppProduct(url *url.URL, p *Product) chatapp.Product {
+	return chatapp.Product{
+		Title:           p.Title,
+		Price:           p.Price,
+		ImageURL:        p.ImageURL,
+		Images:          toChatAppImages(p.Images),
+		Description:     p.Description,
+		RatingsCount:    p.RatingsCount,
+		Rating:          p.Rating,
+		OutOfStock:      p.OutOfStock,
+		OriginalPrice:   p.OriginalPrice,
+		Currency:        p.Currency,
+		Brand:           p.Brand,
+		Features:        p.Features,
+		Seller:          p.Seller,
+		ShipsFrom:       p.ShipsFrom,
+		Prime:           p.Prime,
+		FreeShipping:    p.FreeShipping,
+		Sources:         p.Sources,
+		SelectorVersion: p.SelectorVersion,
+		URL:             url,
+
+		RatingHistogram:   p.RatingHistogram,
+		TopPositiveReview: toChatAppReview(p.TopPositiveReview),
+		TopCriticalReview: toChatAppReview(p.TopCriticalReview),
+
+		Categories:       p.Categories,
+		BestSellersRanks: toChatAppSalesRanks(p.BestSellersRanks),
+
+		VariationDimensions: p.VariationDimensions,
+		Variants:            toChatAppVariants(url, p.Variants),
+
+		Kind:  chatapp.ProductKind(p.Kind),
+		Media: toChatAppMedia(p.Media),
+
+		Coupon:                toChatAppCoupon(p.Coupon),
+		Deal:                  toChatAppDeal(p.Deal),
+		SubscribeAndSavePrice: p.SubscribeAndSavePrice,
+	}
+}
+
+func toChatAppVariants(url *url.URL, variants []Variant) []chatapp.Variant {
+	if len(variants) == 0 {
+		return nil
+	}
+	marketplace, _, _ := ExtractASIN(url.String())
+	result := []chatapp.Variant{}
+	for _, v := range variants {
+		result = append(result, chatapp.Variant{
+			Values:      v.Values,
+			Price:       v.Price,
+			Unavailable: v.Unavailable,
+			Selected:    v.Selected,
+			URL:         CanonicalProductURL(marketplace, v.ASIN),
+		})
+	}
+	return result
+}
+
+func toChatAppReview(r *Review) *chatapp.Review {
+	if r == nil {
+		return nil
+	}
+	return &chatapp.Review{
+		Author: r.Author,
+		Title:  r.Title,
+		Body:   r.Body,
+		Rating: r.Rating,
+	}
+}
+
+func toChatAppSalesRanks(ranks []SalesRank) []chatapp.SalesRank {
+	if len(ranks) == 0 {
+		return nil
+	}
+	result := []chatapp.SalesRank{}
+	for _, r := range ranks {
+		result = append(result, chatapp.SalesRank{Rank: r.Rank, Category: r.Category})
+	}
+	return result
+}
+
+func toChatAppCoupon(c *Coupon) *chatapp.Coupon {
+	if c == nil {
+		return nil
+	}
+	return &chatapp.Coupon{Percent: c.Percent, Amount: c.Amount}
+}
+
+func toChatAppDeal(d *Deal) *chatapp.Deal {
+	if d == nil {
+		return nil
+	}
+	return &chatapp.Deal{Badge: d.Badge, EndsAt: d.EndsAt}
+}
+
+func toChatAppMedia(m *Media) *chatapp.Media {
+	if m == nil {
+		return nil
+	}
+	result := &chatapp.Media{
+		Format:      m.Format,
+		Pages:       m.Pages,
+		Publisher:   m.Publisher,
+		ReleaseDate: m.ReleaseDate,
+		Language:    m.Language,
+		ISBN10:      m.ISBN10,
+		ISBN13:      m.ISBN13,
+	}
+	for _, c := range m.Contributors {
+		result.Contributors = append(result.Contributors, chatapp.Contributor{Name: c.Name, Role: c.Role})
+	}
+	return result
+}
+
+func toChatAppImages(images []Image) []chatapp.Image {
+	if len(images) == 0 {
+		return nil
+	}
+	result := []chatapp.Image{}
+	for _, i := range images {
+		result = append(result, chatapp.Image{URL: i.URL, VideoURL: i.VideoURL})
+	}
+	return result
+}
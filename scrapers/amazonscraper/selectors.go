@@ -0,0 +1,177 @@
+package amazonscraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+//Fields of a SelectorSet
+const (
+	SelectorImage             = "image"
+	SelectorTitle             = "title"
+	SelectorDescription       = "description"
+	SelectorPrice             = "price"
+	SelectorOriginalPrice     = "originalPrice"
+	SelectorOutOfStock        = "outOfStock"
+	SelectorBrand             = "brand"
+	SelectorFeatures          = "features"
+	SelectorSoldBy            = "soldBy"
+	SelectorShipsFrom         = "shipsFrom"
+	SelectorMerchantInfo      = "merchantInfo"
+	SelectorSellerProfile     = "sellerProfile"
+	SelectorPrime             = "prime"
+	SelectorShipping          = "shipping"
+	SelectorRatingsCount      = "ratingsCount"
+	SelectorRating            = "rating"
+	SelectorHistogramRows     = "histogramRows"
+	SelectorReviews           = "reviews"
+	SelectorPositiveReview    = "positiveReview"
+	SelectorCriticalReview    = "criticalReview"
+	SelectorBreadcrumbs       = "breadcrumbs"
+	SelectorBestSellersRank   = "bestSellersRank"
+	SelectorCoupon            = "coupon"
+	SelectorDealBadge         = "dealBadge"
+	SelectorDealTimer         = "dealTimer"
+	SelectorSubscribeAndSave  = "subscribeAndSave"
+	SelectorFormat            = "format"
+	SelectorContributors      = "contributors"
+	SelectorProductDetails    = "productDetails"
+	SelectorGalleryThumbnails = "galleryThumbnails"
+	SelectorProductPage       = "productPage" //Any of these elements makes a page a product page
+)
+
+/*SelectorSet lists, for each field, the CSS selectors ParseProductHTML tries in order
+Usage:
+	Fixing a layout change only needs a new selector set file (see selectors-example.json), not a redeploy
+	Fields missing from a loaded set use DefaultSelectors
+	Version is recorded with reported products, to know which selectors parsed them
+*/
+type SelectorSet struct {
+	Version string              `json:"version"`
+	Fields  map[string][]string `json:"fields"`
+}
+
+//DefaultSelectors are compiled in and used until (and wherever) a loaded SelectorSet doesn't say otherwise
+var DefaultSelectors = SelectorSet{
+	Version: "builtin",
+	Fields: map[string][]string{
+		SelectorImage:             {`#imgTagWrapperId img:last-child`},
+		SelectorTitle:             {"#productTitle"},
+		SelectorDescription:       {`#productDescription`},
+		SelectorPrice:             {"#price_inside_buybox", "#priceblock_ourprice", "#newBuyBoxPrice"},
+		SelectorOriginalPrice:     {"span.priceBlockStrikePriceString.a-text-strike"},
+		SelectorOutOfStock:        {`#almOutOfStockAvailability_feature_div`, `#availability > span`},
+		SelectorBrand:             {"#bylineInfo", "#brand"},
+		SelectorFeatures:          {"#feature-bullets li:not(#replacementPartsFitmentBullet) span.a-list-item"},
+		SelectorSoldBy:            {`.tabular-buybox-text[tabular-attribute-name="Sold by"]`},
+		SelectorShipsFrom:         {`.tabular-buybox-text[tabular-attribute-name="Ships from"]`},
+		SelectorMerchantInfo:      {"#merchant-info"},
+		SelectorSellerProfile:     {"#sellerProfileTriggerId"},
+		SelectorPrime:             {"#prime-badge", "#primeBadge", "#buybox i.a-icon-prime", "#desktop_buybox i.a-icon-prime"},
+		SelectorShipping:          {"#mir-layout-DELIVERY_BLOCK", "#deliveryMessageMirId", "#price-shipping-message", "#ourprice_shippingmessage"},
+		SelectorRatingsCount:      {"#acrCustomerReviewText"},
+		SelectorRating:            {"#acrPopover"},
+		SelectorHistogramRows:     {"#histogramTable tr", "#histogramTable li"},
+		SelectorReviews:           {`#cm-cr-dp-review-list [data-hook="review"]`, `[data-hook="review"]`},
+		SelectorPositiveReview:    {".positive-review"},
+		SelectorCriticalReview:    {".critical-review"},
+		SelectorBreadcrumbs:       {"#wayfinding-breadcrumbs_feature_div ul li a", "#wayfinding-breadcrumbs_container ul li a"},
+		SelectorBestSellersRank:   {"#detailBulletsWrapper_feature_div li", "#SalesRank", "#productDetails_detailBullets_sections1 tr", "#productDetails_db_sections tr", "table.prodDetTable tr"},
+		SelectorCoupon:            {`label[id^="couponText"]`, "#couponBadgeRegularVpc", "#vpcButton", "#promoPriceBlockMessage_feature_div"},
+		SelectorDealBadge:         {"#dealBadge_feature_div .dealBadge", "#dealBadge_feature_div", "#dealBadgeSupportingText"},
+		SelectorDealTimer:         {`[id^="deal_expiry_timer_"]`, "#dealBadgeSupportingText", "#dealBadge_feature_div"},
+		SelectorSubscribeAndSave:  {"#sns-base-price", "#snsPrice .a-offscreen", "#subscriptionPrice"},
+		SelectorFormat:            {"#productSubtitle", "#productBinding", "#binding", "#tmmSwatches .a-button-selected .a-button-inner > a > span:first-child"},
+		SelectorContributors:      {"#bylineInfo .author"},
+		SelectorGalleryThumbnails: {"#altImages li.imageThumbnail img", "#imageBlockThumbs img"},
+		SelectorProductPage:       {"#productTitle", "#dp", "#ppd", "#imgTagWrapperId"},
+		SelectorProductDetails:    {"#detailBullets_feature_div li", "#detailBulletsWrapper_feature_div li", "#productDetailsTable .content li", "#productDetails_detailBullets_sections1 tr", "#productDetails_techSpec_section_1 tr"},
+	},
+}
+
+//Get the selectors of a field, falling back to DefaultSelectors
+func (s *SelectorSet) Get(field string) []string {
+	if selectors := s.Fields[field]; len(selectors) > 0 {
+		return selectors
+	}
+	return DefaultSelectors.Fields[field]
+}
+
+var selectorsMutex sync.RWMutex
+var currentSelectors = &DefaultSelectors
+
+//Selectors returns the SelectorSet currently used by ParseProductHTML
+func Selectors() *SelectorSet {
+	selectorsMutex.RLock()
+	defer selectorsMutex.RUnlock()
+	return currentSelectors
+}
+
+//SetSelectors replaces the SelectorSet used by ParseProductHTML
+func SetSelectors(s *SelectorSet) {
+	selectorsMutex.Lock()
+	defer selectorsMutex.Unlock()
+	currentSelectors = s
+}
+
+//LoadSelectorsFromFile reads a JSON SelectorSet file
+func LoadSelectorsFromFile(filePath string) (*SelectorSet, error) {
+	data, error := ioutil.ReadFile(filePath)
+	if error != nil {
+		return nil, error
+	}
+
+	var selectors SelectorSet
+	if error = json.Unmarshal(data, &selectors); error != nil {
+		return nil, fmt.Errorf("[AmazonScraper] Invalid selector file %s: %w", filePath, error)
+	}
+	if len(selectors.Version) == 0 {
+		return nil, fmt.Errorf("[AmazonScraper] Selector file %s has no version", filePath)
+	}
+	return &selectors, nil
+}
+
+//WatchSelectorsFile loads the file into SetSelectors, then reloads it every interval when it's modified
+//Errors (a half written file, a missing version, ...) are passed to onError and the previous selectors are kept
+//Call the returned function to stop watching
+func WatchSelectorsFile(filePath string, interval time.Duration, onError func(error)) (stop func()) {
+	var lastModified time.Time
+	reload := func() {
+		info, error := os.Stat(filePath)
+		if error != nil {
+			onError(error)
+			return
+		}
+		if !info.ModTime().After(lastModified) {
+			return
+		}
+		lastModified = info.ModTime()
+
+		selectors, error := LoadSelectorsFromFile(filePath)
+		if error != nil {
+			onError(error)
+			return
+		}
+		SetSelectors(selectors)
+	}
+	reload()
+
+	ticker := time.NewTicker(interval)
+	done := make(chan bool)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				reload()
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
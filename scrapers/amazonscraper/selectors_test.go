@@ -0,0 +1,43 @@
+package amazonscraper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSelectorsFromFile(t *testing.T) {
+	directory, _ := ioutil.TempDir("", "selectors")
+	defer os.RemoveAll(directory)
+
+	filePath := filepath.Join(directory, "selectors.json")
+	ioutil.WriteFile(filePath, []byte(`{"version": "2", "fields": {"price": ["#corePrice .a-offscreen"]}}`), 0644)
+
+	selectors, error := LoadSelectorsFromFile(filePath)
+	if error != nil {
+		t.Fatal(error)
+	}
+	if selectors.Version != "2" || selectors.Get(SelectorPrice)[0] != "#corePrice .a-offscreen" {
+		t.Errorf("Loaded: %v", selectors)
+	}
+	if selectors.Get(SelectorTitle)[0] != DefaultSelectors.Fields[SelectorTitle][0] {
+		t.Errorf("Missing fields should use DefaultSelectors, Result: %v", selectors.Get(SelectorTitle))
+	}
+
+	SetSelectors(selectors)
+	defer SetSelectors(&DefaultSelectors)
+
+	product, error := ParseProductHTML([]byte(`<html><body><span id="productTitle">Thing</span><div id="corePrice"><span class="a-offscreen">$12.34</span></div></body></html>`))
+	if error != nil {
+		t.Fatal(error)
+	}
+	if product.Price != 12.34 || product.SelectorVersion != "2" {
+		t.Errorf("Expected: 12.34 (selectors 2) Result: %v (selectors %v)", product.Price, product.SelectorVersion)
+	}
+
+	ioutil.WriteFile(filePath, []byte(`{"fields": {}}`), 0644)
+	if _, error = LoadSelectorsFromFile(filePath); error == nil {
+		t.Error("Expected an error for a selector file without a version")
+	}
+}
@@ -1,30 +1,92 @@
 package amazonscraper
 
 import (
+	"net/url"
 	"regexp"
+	"sort"
 	"strings"
 )
 
+//ExtractManyProductLinkFromString returns every product link (and short link) of s, in the order they appear
 func ExtractManyProductLinkFromString(s string) []string {
-	lines := strings.Split(s, "\n")
-	result := []string{}
-
-	for _, line := range lines {
-		link, success := ExtractOneProductLinkFromString(line)
-		if !success {
-			continue
+	type match struct {
+		start int
+		end   int
+	}
+	matches := []match{}
+	for _, indexes := range productLinkRegex.FindAllStringIndex(s, -1) {
+		matches = append(matches, match{indexes[0], indexes[1]})
+	}
+	for _, indexes := range shortLinkRegex.FindAllStringSubmatchIndex(s, -1) {
+		short := match{indexes[2], indexes[3]}
+		inProductLink := false
+		for _, m := range matches {
+			inProductLink = inProductLink || (short.start < m.end && m.start < short.end)
+		}
+		if !inProductLink {
+			matches = append(matches, short)
 		}
-		result = append(result, link)
 	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].start < matches[j].start
+	})
 
+	result := []string{}
+	for _, m := range matches {
+		result = append(result, trimLinkPunctuation(s[m.start:m.end]))
+	}
 	return result
 }
 
 func ExtractOneProductLinkFromString(s string) (link string, found bool) {
-	regex, _ := regexp.Compile(`(http[s]?:\/\/)?(www\.)?amazon\..*\/.*(dp|gp)\/\S*`)
-	link = regex.FindString(s)
-	found = len(link) > 0
-	return link, found
+	links := ExtractManyProductLinkFromString(s)
+	if len(links) == 0 {
+		return "", false
+	}
+	return links[0], true
+}
+
+//productLinkRegex matches links to product pages ("amazon.com/Title/dp/ASIN", "amazon.co.uk/gp/product/ASIN"). They end at whitespace or <>, see trimLinkPunctuation
+var productLinkRegex = regexp.MustCompile(`(http[s]?:\/\/)?(www\.)?amazon\.[a-z]+(\.[a-z]+)?\/(\S*?\/)?(dp|gp)\/[^\s<>]*`)
+
+//trimLinkPunctuation removes the punctuation of the sentence around a link ("(see https://amzn.to/x).")
+//Closing brackets are kept when the link opens them
+func trimLinkPunctuation(link string) string {
+	for len(link) > 0 {
+		last := link[len(link)-1]
+		switch {
+		case strings.IndexByte(".,;:!?'\"*_~", last) >= 0:
+		case last == ')' && strings.Count(link, "(") < strings.Count(link, ")"):
+		case last == ']' && strings.Count(link, "[") < strings.Count(link, "]"):
+		default:
+			return link
+		}
+		link = link[:len(link)-1]
+	}
+	return link
+}
+
+//ShortLinkHosts are Amazon's link shortener domains. They redirect to a product page
+var ShortLinkHosts = []string{"amzn.to", "a.co", "amzn.eu", "amzn.asia", "amzn.com"}
+
+var shortLinkRegex = regexp.MustCompile(`(?:^|[^\w.-])((http[s]?:\/\/)?(www\.)?(amzn\.to|a\.co|amzn\.eu|amzn\.asia|amzn\.com)\/[^\s/]\S*)`)
+
+//IsShortLink returns true when the link uses one of the ShortLinkHosts
+func IsShortLink(s string) bool {
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	URL, error := url.Parse(s)
+	if error != nil {
+		return false
+	}
+	host := strings.TrimPrefix(strings.ToLower(URL.Hostname()), "www.")
+	for _, shortHost := range ShortLinkHosts {
+		if host == shortHost {
+			return len(strings.Trim(URL.Path, "/")) > 0
+		}
+	}
+	return false
 }
 
 func IsProductLink(s string) bool {
@@ -1,6 +1,9 @@
 package amazonscraper
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestIsProductLink(t *testing.T) {
 	testTable := []struct {
@@ -31,6 +34,26 @@ func TestIsProductLink(t *testing.T) {
 			input:    "https://www.amazon.com",
 			expected: false,
 		},
+		{
+			input:    "https://amzn.to/3fGZx2b",
+			expected: true,
+		},
+		{
+			input:    "check this out https://a.co/d/7Xk2mPq",
+			expected: true,
+		},
+		{
+			input:    "https://amzn.to/",
+			expected: false,
+		},
+		{
+			input:    "https://amzn.top/3fGZx2b",
+			expected: false,
+		},
+		{
+			input:    "https://mocha.co/menu",
+			expected: false,
+		},
 	}
 
 	for _, test := range testTable {
@@ -40,3 +63,38 @@ func TestIsProductLink(t *testing.T) {
 		}
 	}
 }
+
+func TestExtractManyProductLinkFromString(t *testing.T) {
+	testTable := []struct {
+		input    string
+		expected []string
+	}{
+		{
+			input:    "https://www.amazon.com/dp/B000000001 or https://www.amazon.com/dp/B000000002?",
+			expected: []string{"https://www.amazon.com/dp/B000000001", "https://www.amazon.com/dp/B000000002"},
+		},
+		{
+			input:    "Cheaper (https://www.amazon.com/Acer-R240HY/dp/B0148NNKTC/ref=sr_1_2?th=1). Or https://amzn.to/3fGZx2b, maybe",
+			expected: []string{"https://www.amazon.com/Acer-R240HY/dp/B0148NNKTC/ref=sr_1_2?th=1", "https://amzn.to/3fGZx2b"},
+		},
+		{
+			input:    "a.co/d/7Xk2mPq\nwww.amazon.co.uk/gp/product/B07MPCSHQD",
+			expected: []string{"a.co/d/7Xk2mPq", "www.amazon.co.uk/gp/product/B07MPCSHQD"},
+		},
+		{
+			input:    "<https://www.amazon.de/Title_(2020)/dp/B000000001>",
+			expected: []string{"https://www.amazon.de/Title_(2020)/dp/B000000001"},
+		},
+		{
+			input:    "https://www.amazon.com is the store",
+			expected: []string{},
+		},
+	}
+
+	for _, test := range testTable {
+		result := ExtractManyProductLinkFromString(test.input)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("Input: %v Expected: %v Result: %v", test.input, test.expected, result)
+		}
+	}
+}
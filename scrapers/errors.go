@@ -0,0 +1,10 @@
+package scrapers
+
+import "errors"
+
+//Errors returned (wrapped) by Scrapers when a page isn't a usable product page. Check with errors.Is
+var (
+	ErrBlocked         = errors.New("the retailer blocked the request with a bot check")
+	ErrProductNotFound = errors.New("the product page doesn't exist")
+	ErrNotProductPage  = errors.New("the page isn't a product page")
+)
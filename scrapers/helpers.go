@@ -2,11 +2,17 @@ package scrapers
 
 import (
 	"strconv"
+	"strings"
 	"unicode"
 )
 
 //NumbersFromString Takes all numbers from a string and returns a slice of floats
 func NumbersFromString(input string) (numbers []float64) {
+	return NumbersFromStringWithLocale(input, LocaleEnglish)
+}
+
+//NumbersFromStringWithLocale Same as NumbersFromString, using the locale's decimal and group separators
+func NumbersFromStringWithLocale(input string, locale Locale) (numbers []float64) {
 	resultString := ""
 
 	tryToAppendString := func(resultString *string) {
@@ -20,11 +26,12 @@ func NumbersFromString(input string) (numbers []float64) {
 	}
 
 	for _, c := range input {
-		if unicode.IsDigit(c) || c == '.' || c == ',' {
-			if c == ',' {
-				continue
-			}
+		if c == locale.DecimalSeparator {
+			resultString += "."
+		} else if unicode.IsDigit(c) {
 			resultString += string(c)
+		} else if strings.ContainsRune(locale.GroupSeparators, c) && len(resultString) > 0 {
+			continue
 		} else {
 			tryToAppendString(&resultString)
 		}
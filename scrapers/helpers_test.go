@@ -21,3 +21,57 @@ func TestNumbersFromString(t *testing.T) {
 	test(t, "   2.30    \n57 ", []float64{2.30, 57})
 	test(t, "1,653 ratings", []float64{1653})
 }
+
+func testWithLocale(t *testing.T, testString string, locale Locale, expectedResult []float64) {
+	result := NumbersFromStringWithLocale(testString, locale)
+
+	if len(result) != len(expectedResult) {
+		t.Errorf("%v %v", expectedResult, result)
+		return
+	}
+
+	for index, result := range result {
+		expected := expectedResult[index]
+		if result != expected {
+			t.Errorf("[%v]: %v %v", index, expected, result)
+		}
+	}
+}
+
+func TestNumbersFromStringWithLocale(t *testing.T) {
+	testWithLocale(t, "$1,299.99", LocaleEnglish, []float64{1299.99})
+	testWithLocale(t, "1.299,99 €", LocaleEuropean, []float64{1299.99})
+	testWithLocale(t, "4,5 von 5 Sternen", LocaleEuropean, []float64{4.5, 5})
+	testWithLocale(t, "1 299,99 €", LocaleSpaced, []float64{1299.99})
+	testWithLocale(t, "1\u202f299,99\u00a0€", LocaleSpaced, []float64{1299.99})
+}
+
+func TestParsePrice(t *testing.T) {
+	testTable := []struct {
+		input           string
+		locale          Locale
+		defaultCurrency string
+		price           float64
+		currency        string
+		found           bool
+	}{
+		{"$24.99", LocaleEnglish, "USD", 24.99, "USD", true},
+		{"$24.99", LocaleEnglish, "CAD", 24.99, "CAD", true},
+		{"CDN$ 1,024.50", LocaleEnglish, "USD", 1024.50, "CAD", true},
+		{"US$ 12.00", LocaleEnglish, "SGD", 12, "USD", true},
+		{"S$ 12.00", LocaleEnglish, "USD", 12, "SGD", true},
+		{"12,00 EUR", LocaleEuropean, "USD", 12, "EUR", true},
+		{"NECESSARY 12", LocaleEnglish, "USD", 12, "USD", true},
+		{"1.299,99 €", LocaleEuropean, "EUR", 1299.99, "EUR", true},
+		{"£7.49", LocaleEnglish, "GBP", 7.49, "GBP", true},
+		{"￥3,480", LocaleEnglish, "JPY", 3480, "JPY", true},
+		{"Currently unavailable", LocaleEnglish, "USD", 0, "USD", false},
+	}
+
+	for _, test := range testTable {
+		price, currency, found := ParsePrice(test.input, test.locale, test.defaultCurrency)
+		if price != test.price || currency != test.currency || found != test.found {
+			t.Errorf("Input: %v Expected: %v %v %v Result: %v %v %v", test.input, test.price, test.currency, test.found, price, currency, found)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package scrapers
+
+import (
+	"regexp"
+	"strings"
+)
+
+//Locale describes how a marketplace writes numbers
+type Locale struct {
+	DecimalSeparator rune
+	GroupSeparators  string //Thousands separators, dropped while parsing
+}
+
+//Common locales
+var (
+	LocaleEnglish  = Locale{DecimalSeparator: '.', GroupSeparators: ","}              //1,299.99
+	LocaleEuropean = Locale{DecimalSeparator: ',', GroupSeparators: "."}              //1.299,99
+	LocaleSpaced   = Locale{DecimalSeparator: ',', GroupSeparators: " \u00a0\u202f."} //1 299,99
+)
+
+//currencySymbols maps symbols found in price text to ISO 4217 codes. Longer symbols come first, so "US$" isn't read as "S$"
+var currencySymbols = []struct {
+	symbol   string
+	currency string
+}{
+	{"CDN$", "CAD"},
+	{"MX$", "MXN"},
+	{"US$", "USD"},
+	{"A$", "AUD"},
+	{"C$", "CAD"},
+	{"R$", "BRL"},
+	{"S$", "SGD"},
+	{"€", "EUR"},
+	{"£", "GBP"},
+	{"￥", "JPY"},
+	{"¥", "JPY"},
+	{"₹", "INR"},
+	{"₺", "TRY"},
+	{"zł", "PLN"},
+}
+
+var currencyCodes = []string{"USD", "CAD", "MXN", "BRL", "GBP", "EUR", "JPY", "INR", "AUD", "SGD", "TRY", "PLN", "SEK", "AED", "SAR", "EGP"}
+
+//currencyCodeRegex matches currency codes as whole words ("12 EUR", "EUR12"), not inside other words ("NECESSARY")
+var currencyCodeRegex = regexp.MustCompile(`(?:^|[^A-Z])(` + strings.Join(currencyCodes, "|") + `)(?:[^A-Z]|$)`)
+
+//DetectCurrency returns the ISO 4217 code of the currency used in the input
+//"$" and anything unrecognised returns defaultCurrency, since "$" is shared by many currencies
+func DetectCurrency(input string, defaultCurrency string) string {
+	for _, c := range currencySymbols {
+		if strings.Contains(input, c.symbol) {
+			return c.currency
+		}
+	}
+	if match := currencyCodeRegex.FindStringSubmatch(strings.ToUpper(input)); match != nil {
+		return match[1]
+	}
+	return defaultCurrency
+}
+
+//ParsePrice reads the first number in the input using the locale, and detects its currency
+func ParsePrice(input string, locale Locale, defaultCurrency string) (price float64, currency string, found bool) {
+	numbers := NumbersFromStringWithLocale(input, locale)
+	if len(numbers) == 0 {
+		return 0, defaultCurrency, false
+	}
+	return numbers[0], DetectCurrency(input, defaultCurrency), true
+}
@@ -0,0 +1,109 @@
+package scrapers
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/programmingparody/amazing-bot/chatapp"
+)
+
+/*Scraper is implemented by every retailer package (see scrapers/amazonscraper)
+Usage:
+	ExtractLinks / IsProductLink find the retailer's product links in a chat message
+	IsShortLink reports links that must have their redirects followed before they can be identified
+	IsStoreURL reports links to the retailer's own site, the only redirects followed from a short link besides other short links
+	ProductKey / CanonicalURL identify a product no matter which form of link was posted
+	ParseProductHTML turns a product page into the retailer's own product type
+	ToChatAppProduct converts the result of ParseProductHTML into a chatapp.Product
+*/
+type Scraper interface {
+	ExtractLinks(s string) []string
+	IsProductLink(s string) bool
+	IsShortLink(url *url.URL) bool
+	IsStoreURL(url *url.URL) bool
+	ProductKey(url *url.URL) (ProductKey, error)
+	CanonicalURL(key ProductKey) *url.URL
+	ParseProductHTML(url *url.URL, html []byte) (interface{}, error)
+	ToChatAppProduct(url *url.URL, product interface{}) (chatapp.Product, error)
+}
+
+//Searcher is implemented by Scrapers that can search the retailer by keywords
+type Searcher interface {
+	SearchURL(marketplace string, keywords string) *url.URL
+	//ParseSearchHTML returns lightweight products (title, price, rating, image) found on a search results page
+	ParseSearchHTML(url *url.URL, html []byte) ([]chatapp.Product, error)
+}
+
+//OfferScraper is implemented by Scrapers that can list a product's offers from other sellers (new, used, renewed, ...)
+type OfferScraper interface {
+	OfferListingURL(key ProductKey) *url.URL
+	ParseOffersHTML(url *url.URL, html []byte) ([]chatapp.Offer, error)
+}
+
+//Localizer is implemented by Scrapers whose stores don't all write numbers the same way ("19,99 €" on amazon.de)
+type Localizer interface {
+	Locale(url *url.URL) Locale
+}
+
+//ProductKey uniquely identifies a product of a retailer
+type ProductKey struct {
+	Marketplace string //Regional store of the retailer (For Amazon, the TLD: "com", "co.uk", ...)
+	ID          string //Retailer's product ID (For Amazon, the ASIN)
+}
+
+func (k ProductKey) String() string {
+	return fmt.Sprintf("%s/%s", k.Marketplace, k.ID)
+}
+
+//Registry holds the Scrapers the bot and fetchers consult
+type Registry struct {
+	scrapers []Scraper
+}
+
+//NewRegistry returns a Registry containing scrapers, in order of priority
+func NewRegistry(scrapers ...Scraper) *Registry {
+	r := &Registry{}
+	for _, s := range scrapers {
+		r.Register(s)
+	}
+	return r
+}
+
+//Register a Scraper. Scrapers registered first are consulted first
+func (r *Registry) Register(s Scraper) {
+	r.scrapers = append(r.scrapers, s)
+}
+
+//ExtractLinks returns every product link, from every registered Scraper, found in s. Links are in the order they appear in s
+func (r *Registry) ExtractLinks(s string) []string {
+	result := []string{}
+	for _, scraper := range r.scrapers {
+		result = append(result, scraper.ExtractLinks(s)...)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return strings.Index(s, result[i]) < strings.Index(s, result[j])
+	})
+	return result
+}
+
+//Searcher returns the first registered Scraper that implements Searcher
+func (r *Registry) Searcher() (Searcher, error) {
+	for _, scraper := range r.scrapers {
+		if searcher, ok := scraper.(Searcher); ok {
+			return searcher, nil
+		}
+	}
+	return nil, fmt.Errorf("[Registry] No scraper supports searching")
+}
+
+//ScraperFor returns the first Scraper that recognises the URL as a product link
+func (r *Registry) ScraperFor(url *url.URL) (Scraper, error) {
+	for _, scraper := range r.scrapers {
+		if scraper.IsProductLink(url.String()) {
+			return scraper, nil
+		}
+	}
+	return nil, fmt.Errorf("[Registry] No scraper found for: %s", url)
+}
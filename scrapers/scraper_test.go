@@ -0,0 +1,62 @@
+package scrapers
+
+import (
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/programmingparody/amazing-bot/chatapp"
+)
+
+//hostScraper recognises links to its host
+type hostScraper struct {
+	host string
+}
+
+func (s hostScraper) ExtractLinks(text string) []string {
+	return regexp.MustCompile(`https://`+regexp.QuoteMeta(s.host)+`/\S+`).FindAllString(text, -1)
+}
+func (s hostScraper) IsProductLink(link string) bool {
+	return strings.HasPrefix(link, "https://"+s.host+"/")
+}
+func (s hostScraper) IsShortLink(url *url.URL) bool { return false }
+func (s hostScraper) IsStoreURL(url *url.URL) bool  { return url.Host == s.host }
+func (s hostScraper) ProductKey(url *url.URL) (ProductKey, error) {
+	return ProductKey{ID: url.Path}, nil
+}
+func (s hostScraper) CanonicalURL(key ProductKey) *url.URL { return nil }
+func (s hostScraper) ParseProductHTML(url *url.URL, html []byte) (interface{}, error) {
+	return nil, nil
+}
+func (s hostScraper) ToChatAppProduct(url *url.URL, product interface{}) (chatapp.Product, error) {
+	return chatapp.Product{}, nil
+}
+
+func TestRegistry(t *testing.T) {
+	first, second := hostScraper{host: "shop.example"}, hostScraper{host: "store.example"}
+	registry := NewRegistry(first, second)
+
+	links := registry.ExtractLinks("https://store.example/1 then https://shop.example/2 and https://store.example/3")
+	expected := []string{"https://store.example/1", "https://shop.example/2", "https://store.example/3"}
+	if !reflect.DeepEqual(links, expected) {
+		t.Errorf("Expected the links in the order of the text: %v Result: %v", expected, links)
+	}
+
+	tests := []struct {
+		link     string
+		expected Scraper
+	}{
+		{"https://shop.example/product", first},
+		{"https://store.example/product", second},
+		{"https://unknown.example/product", nil},
+	}
+	for _, test := range tests {
+		URL, _ := url.Parse(test.link)
+		scraper, error := registry.ScraperFor(URL)
+		if scraper != test.expected || (test.expected == nil) != (error != nil) {
+			t.Errorf("%s: Expected: %v Result: %v %v", test.link, test.expected, scraper, error)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/programmingparody/amazing-bot/chatapp"
+	"github.com/programmingparody/amazing-bot/scrapers"
+)
+
+//watchItem is a product tracked by a user
+type watchItem struct {
+	Platform    string    `json:"platform"` //chatapp.Notifier.Platform of the session the user tracked from
+	UserID      string    `json:"userID"`
+	ChannelID   string    `json:"channelID"` //Channel the user is mentioned in. Empty sends direct messages
+	Key         string    `json:"key"`       //scrapers.ProductKey of the product
+	URL         string    `json:"url"`
+	Title       string    `json:"title"`
+	TargetPrice float32   `json:"targetPrice"` //Alerts when the price falls below it
+	Currency    string    `json:"currency"`
+	LastPrice   float32   `json:"lastPrice"`
+	OutOfStock  bool      `json:"outOfStock"`
+	Added       time.Time `json:"added"`
+}
+
+/*
+tracker keeps the users' watchlists, re-fetches tracked products every Interval through Fetcher,
+and notifies users when a price falls below their target or an out of stock product is back
+*/
+type tracker struct {
+	Fetcher      ProductFetcher
+	Scrapers     *scrapers.Registry
+	Interval     time.Duration
+	MaxPerUser   int
+	Path         string //JSON file the watchlists are saved to. Empty keeps them in memory
+	ErrorHandler func(error)
+
+	mutex     sync.Mutex
+	items     []*watchItem
+	notifiers map[string]chatapp.Notifier
+}
+
+func (t *tracker) load() error {
+	if len(t.Path) == 0 {
+		return nil
+	}
+	data, error := ioutil.ReadFile(t.Path)
+	if os.IsNotExist(error) {
+		return nil
+	}
+	if error != nil {
+		return error
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return json.Unmarshal(data, &t.items)
+}
+
+//save the watchlists. t.mutex must be held
+func (t *tracker) save() error {
+	if len(t.Path) == 0 {
+		return nil
+	}
+	data, error := json.Marshal(t.items)
+	if error != nil {
+		return error
+	}
+	return ioutil.WriteFile(t.Path, data, 0644)
+}
+
+//addNotifier lets the tracker alert the users of a session
+func (t *tracker) addNotifier(n chatapp.Notifier) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.notifiers == nil {
+		t.notifiers = make(map[string]chatapp.Notifier)
+	}
+	t.notifiers[n.Platform()] = n
+}
+
+func (t *tracker) productKey(URL *url.URL) (string, error) {
+	scraper, error := t.Scrapers.ScraperFor(URL)
+	if error != nil {
+		return "", error
+	}
+	key, error := scraper.ProductKey(URL)
+	return key.String(), error
+}
+
+//Track a product for a user. Tracking a product again replaces its target and channel
+//targetPrice 0 alerts on any drop below the current price
+func (t *tracker) Track(platform string, userID string, channelID string, p *chatapp.Product, targetPrice float32) error {
+	key, error := t.productKey(p.URL)
+	if error != nil {
+		return error
+	}
+	if targetPrice <= 0 {
+		targetPrice = p.Price
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	count := 0
+	for _, item := range t.items {
+		if item.Platform != platform || item.UserID != userID {
+			continue
+		}
+		if item.Key == key {
+			item.ChannelID, item.TargetPrice = channelID, targetPrice
+			return t.save()
+		}
+		count++
+	}
+	if count >= t.MaxPerUser {
+		return fmt.Errorf("You're already tracking %d products, untrack one first", t.MaxPerUser)
+	}
+
+	t.items = append(t.items, &watchItem{
+		Platform:    platform,
+		UserID:      userID,
+		ChannelID:   channelID,
+		Key:         key,
+		URL:         p.URL.String(),
+		Title:       p.Title,
+		TargetPrice: targetPrice,
+		Currency:    p.Currency,
+		LastPrice:   p.Price,
+		OutOfStock:  p.OutOfStock,
+		Added:       time.Now(),
+	})
+	return t.save()
+}
+
+//Untrack removes a product from a user's watchlist. found is false when the user wasn't tracking it
+func (t *tracker) Untrack(platform string, userID string, URL *url.URL) (found bool, e error) {
+	key, error := t.productKey(URL)
+	if error != nil {
+		return false, error
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for index, item := range t.items {
+		if item.Platform == platform && item.UserID == userID && item.Key == key {
+			t.items = append(t.items[:index], t.items[index+1:]...)
+			return true, t.save()
+		}
+	}
+	return false, nil
+}
+
+//Watchlist of a user, oldest first
+func (t *tracker) Watchlist(platform string, userID string) []watchItem {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	result := []watchItem{}
+	for _, item := range t.items {
+		if item.Platform == platform && item.UserID == userID {
+			result = append(result, *item)
+		}
+	}
+	return result
+}
+
+//Start checking tracked products every Interval, until stop is called
+func (t *tracker) Start() (stop func()) {
+	ticker := time.NewTicker(t.Interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				t.check()
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+//check re-fetches every tracked product once, and notifies the users it changed for
+func (t *tracker) check() {
+	t.mutex.Lock()
+	urls := map[string]string{}
+	for _, item := range t.items {
+		urls[item.Key] = item.URL
+	}
+	t.mutex.Unlock()
+
+	for key, link := range urls {
+		URL, error := url.Parse(link)
+		if error != nil {
+			t.ErrorHandler(error)
+			continue
+		}
+		product, error := t.Fetcher.Fetch(URL)
+		if error != nil {
+			t.ErrorHandler(error)
+			continue
+		}
+		t.update(key, product)
+	}
+}
+
+//update the items tracking a product with its latest state, notifying users of price drops and restocks
+func (t *tracker) update(key string, p *chatapp.Product) {
+	type alert struct {
+		notifier     chatapp.Notifier
+		notification *chatapp.Notification
+	}
+	alerts := []alert{}
+
+	t.mutex.Lock()
+	for _, item := range t.items {
+		if item.Key != key {
+			continue
+		}
+		text := ""
+		switch {
+		case item.OutOfStock && !p.OutOfStock:
+			text = "Back in stock!"
+		case p.Price > 0 && p.Price < item.TargetPrice && (item.LastPrice == 0 || item.LastPrice >= item.TargetPrice):
+			text = fmt.Sprintf("Price drop! Now %s, below your target of %s", chatapp.FormatPrice(p.Price, p.Currency), chatapp.FormatPrice(item.TargetPrice, p.Currency))
+		}
+		item.LastPrice, item.OutOfStock = p.Price, p.OutOfStock
+
+		if notifier := t.notifiers[item.Platform]; len(text) > 0 && notifier != nil {
+			alerts = append(alerts, alert{notifier, &chatapp.Notification{
+				UserID:    item.UserID,
+				ChannelID: item.ChannelID,
+				Text:      text,
+				Product:   p,
+			}})
+		}
+	}
+	error := t.save()
+	t.mutex.Unlock()
+	if error != nil {
+		t.ErrorHandler(error)
+	}
+
+	for _, a := range alerts {
+		if error := a.notifier.Notify(a.notification); error != nil {
+			t.ErrorHandler(error)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/programmingparody/amazing-bot/chatapp"
+	"github.com/programmingparody/amazing-bot/scrapers"
+)
+
+//trackingPlatform returns the platform of a session when products can be tracked from it
+func (ab *AmazingBot) trackingPlatform(s chatapp.Session) (string, bool) {
+	notifier, ok := s.(chatapp.Notifier)
+	if !ok || ab.Tracker == nil {
+		return "", false
+	}
+	return notifier.Platform(), true
+}
+
+//track <link> [target price] [dm]
+func (ab *AmazingBot) track(s chatapp.Session, c *chatapp.Command) {
+	usage := fmt.Sprintf("Usage: `%s track <link> [target price] [dm]`", chatapp.CommandPrefix)
+	platform, ok := ab.trackingPlatform(s)
+	if !ok {
+		c.Message.Actions.RespondWithText("Tracking isn't enabled")
+		return
+	}
+	if len(c.Args) == 0 {
+		c.Message.Actions.RespondWithText(usage)
+		return
+	}
+	URL, error := url.Parse(c.Args[0])
+	if error != nil || !ab.isProductLink(URL) {
+		c.Message.Actions.RespondWithText(usage)
+		return
+	}
+
+	targetPriceText := ""
+	channelID := c.Message.ChannelID
+	for _, arg := range c.Args[1:] {
+		if strings.EqualFold(arg, "dm") {
+			channelID = ""
+		} else if _, _, found := scrapers.ParsePrice(arg, scrapers.LocaleEnglish, ""); found {
+			targetPriceText = arg
+		} else {
+			c.Message.Actions.RespondWithText(usage)
+			return
+		}
+	}
+
+	product, error := ab.Fetcher.Fetch(URL)
+	if error != nil {
+		ab.handleFetchError(c.Message, error)
+		return
+	}
+	var targetPrice float32
+	if len(targetPriceText) > 0 {
+		price, refusal := ab.parseTargetPrice(targetPriceText, product)
+		if len(refusal) > 0 {
+			c.Message.Actions.RespondWithText(refusal)
+			return
+		}
+		targetPrice = price
+	}
+	if error = ab.Tracker.Track(platform, c.Message.AuthorID, channelID, product, targetPrice); error != nil {
+		c.Message.Actions.RespondWithText(error.Error())
+		return
+	}
+	c.Message.Actions.RespondWithText(trackingText(product, targetPrice))
+}
+
+//untrack <link or watchlist number>
+func (ab *AmazingBot) untrack(s chatapp.Session, c *chatapp.Command) {
+	platform, ok := ab.trackingPlatform(s)
+	if !ok {
+		c.Message.Actions.RespondWithText("Tracking isn't enabled")
+		return
+	}
+	if len(c.Args) == 0 {
+		c.Message.Actions.RespondWithText(fmt.Sprintf("Usage: `%s untrack <link or watchlist number>`", chatapp.CommandPrefix))
+		return
+	}
+
+	link := c.Args[0]
+	if number, error := strconv.Atoi(link); error == nil {
+		watchlist := ab.Tracker.Watchlist(platform, c.Message.AuthorID)
+		if number < 1 || number > len(watchlist) {
+			c.Message.Actions.RespondWithText(fmt.Sprintf("Your watchlist has no product #%d", number))
+			return
+		}
+		link = watchlist[number-1].URL
+	}
+	URL, error := url.Parse(link)
+	if error != nil || !ab.isProductLink(URL) {
+		c.Message.Actions.RespondWithText(fmt.Sprintf("Usage: `%s untrack <link or watchlist number>`", chatapp.CommandPrefix))
+		return
+	}
+
+	found, error := ab.Tracker.Untrack(platform, c.Message.AuthorID, URL)
+	switch {
+	case error != nil:
+		c.Message.Actions.RespondWithText(error.Error())
+	case !found:
+		c.Message.Actions.RespondWithText("You weren't tracking that product")
+	default:
+		c.Message.Actions.RespondWithText("Stopped tracking it")
+	}
+}
+
+//watchlist lists the products the user tracks
+func (ab *AmazingBot) watchlist(s chatapp.Session, c *chatapp.Command) {
+	platform, ok := ab.trackingPlatform(s)
+	if !ok {
+		c.Message.Actions.RespondWithText("Tracking isn't enabled")
+		return
+	}
+	watchlist := ab.Tracker.Watchlist(platform, c.Message.AuthorID)
+	if len(watchlist) == 0 {
+		c.Message.Actions.RespondWithText(fmt.Sprintf("You're not tracking anything. Try `%s track <link>`", chatapp.CommandPrefix))
+		return
+	}
+	lines := []string{fmt.Sprintf("Your watchlist (%d/%d):", len(watchlist), ab.Tracker.MaxPerUser)}
+	for index, item := range watchlist {
+		lines = append(lines, fmt.Sprintf("%d. %s (below %s) %s", index+1, item.Title, chatapp.FormatPrice(item.TargetPrice, item.Currency), item.URL))
+	}
+	c.Message.Actions.RespondWithText(strings.Join(lines, "\n"))
+}
+
+//handleTrackRequest tracks a product the bot posted, for the user who reacted to it
+func (ab *AmazingBot) handleTrackRequest(s chatapp.Session, r *chatapp.TrackRequest) {
+	notifier, ok := s.(chatapp.Notifier)
+	if !ok || ab.SentProducts == nil {
+		return
+	}
+	//Expired products are still returned, and good enough to be tracked
+	product, _ := ab.SentProducts.Get(r.MessageID)
+	if product == nil {
+		return
+	}
+
+	text := trackingText(product, 0)
+	if error := ab.Tracker.Track(notifier.Platform(), r.UserID, r.ChannelID, product, 0); error != nil {
+		text = error.Error()
+	}
+	notifier.Notify(&chatapp.Notification{UserID: r.UserID, ChannelID: r.ChannelID, Text: text})
+}
+
+func (ab *AmazingBot) isProductLink(URL *url.URL) bool {
+	_, error := ab.Scrapers.ScraperFor(URL)
+	return error == nil
+}
+
+//maxTargetPriceRatio is how far above the current price a target price can be. Higher ones are typos, or read with the wrong separators
+const maxTargetPriceRatio = 2
+
+//decimalPriceRegex matches prices with cents and no thousands separators, which read the same in every locale ("19.99", "19,99 €")
+var decimalPriceRegex = regexp.MustCompile(`^\D*(\d+)[.,](\d{1,2})\D*$`)
+
+//parseTargetPrice reads a price typed by a user the way the product's store writes prices ("1.500" on amazon.de),
+//or the English way when only that reading is close to the current price
+//refusal tells the user why the price can't be used, when it's ambiguous or far above the current price
+func (ab *AmazingBot) parseTargetPrice(text string, p *chatapp.Product) (price float32, refusal string) {
+	locale := scrapers.LocaleEnglish
+	if scraper, error := ab.Scrapers.ScraperFor(p.URL); error == nil {
+		if localizer, ok := scraper.(scrapers.Localizer); ok {
+			locale = localizer.Locale(p.URL)
+		}
+	}
+	readings := []float32{}
+	if match := decimalPriceRegex.FindStringSubmatch(text); match != nil {
+		value, _ := strconv.ParseFloat(match[1]+"."+match[2], 32)
+		readings = append(readings, float32(value))
+	} else {
+		storePrice, _, _ := scrapers.ParsePrice(text, locale, "")
+		englishPrice, _, _ := scrapers.ParsePrice(text, scrapers.LocaleEnglish, "")
+		readings = append(readings, float32(storePrice))
+		if englishPrice != storePrice {
+			readings = append(readings, float32(englishPrice))
+		}
+	}
+
+	if p.Price > 0 {
+		plausible := []float32{}
+		for _, reading := range readings {
+			if reading <= p.Price*maxTargetPriceRatio {
+				plausible = append(plausible, reading)
+			}
+		}
+		if len(plausible) == 0 {
+			return 0, fmt.Sprintf("%s is far above the current price of %s, check the target price", chatapp.FormatPrice(readings[0], p.Currency), chatapp.FormatPrice(p.Price, p.Currency))
+		}
+		readings = plausible
+	}
+	if len(readings) > 1 {
+		return 0, fmt.Sprintf("Is %s %s or %s? Write it without thousands separators", text, chatapp.FormatPrice(readings[0], p.Currency), chatapp.FormatPrice(readings[1], p.Currency))
+	}
+	return readings[0], ""
+}
+
+func trackingText(p *chatapp.Product, targetPrice float32) string {
+	if targetPrice <= 0 {
+		targetPrice = p.Price
+	}
+	if targetPrice <= 0 {
+		return fmt.Sprintf("Tracking \"%s\". I'll let you know when it's back in stock", p.Title)
+	}
+	if p.Price > 0 && p.Price < targetPrice {
+		return fmt.Sprintf("Tracking \"%s\". It's already below %s, at %s. I'll let you know when it drops below it again, or when it's back in stock", p.Title, chatapp.FormatPrice(targetPrice, p.Currency), chatapp.FormatPrice(p.Price, p.Currency))
+	}
+	return fmt.Sprintf("Tracking \"%s\". I'll let you know when it's below %s or back in stock", p.Title, chatapp.FormatPrice(targetPrice, p.Currency))
+}
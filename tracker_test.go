@@ -0,0 +1,191 @@
+package main
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/programmingparody/amazing-bot/chatapp"
+	"github.com/programmingparody/amazing-bot/scrapers"
+	"github.com/programmingparody/amazing-bot/scrapers/amazonscraper"
+)
+
+type testFetcher struct {
+	product *chatapp.Product
+}
+
+func (f *testFetcher) Fetch(url *url.URL) (*chatapp.Product, error) {
+	return f.product, nil
+}
+
+type testNotifier struct {
+	notifications []*chatapp.Notification
+}
+
+func (n *testNotifier) Platform() string {
+	return "test"
+}
+
+func (n *testNotifier) Notify(notification *chatapp.Notification) error {
+	n.notifications = append(n.notifications, notification)
+	return nil
+}
+
+func TestTracker(t *testing.T) {
+	URL, _ := url.Parse("https://www.amazon.com/dp/B07MPCSHQD")
+	fetcher := &testFetcher{product: &chatapp.Product{Title: "Keyboard", Price: 50, URL: URL}}
+	notifier := &testNotifier{}
+	tracker := &tracker{
+		Fetcher:      fetcher,
+		Scrapers:     scrapers.NewRegistry(amazonscraper.Scraper{}),
+		MaxPerUser:   1,
+		ErrorHandler: func(e error) { t.Error(e) },
+	}
+	tracker.addNotifier(notifier)
+
+	if error := tracker.Track("test", "user", "channel", fetcher.product, 40); error != nil {
+		t.Fatal(error)
+	}
+	other, _ := url.Parse("https://www.amazon.com/dp/B000000000")
+	if error := tracker.Track("test", "user", "", &chatapp.Product{URL: other}, 0); error == nil {
+		t.Error("Expected an error when tracking more than MaxPerUser products")
+	}
+
+	steps := []struct {
+		product       chatapp.Product
+		notifications int
+	}{
+		{product: chatapp.Product{Price: 45}, notifications: 0},
+		{product: chatapp.Product{Price: 39}, notifications: 1},
+		{product: chatapp.Product{Price: 35}, notifications: 1}, //Still below the target, already notified
+		{product: chatapp.Product{OutOfStock: true}, notifications: 1},
+		{product: chatapp.Product{Price: 38}, notifications: 2}, //Back in stock
+	}
+	for index, step := range steps {
+		step.product.URL = URL
+		fetcher.product = &step.product
+		tracker.check()
+		if len(notifier.notifications) != step.notifications {
+			t.Fatalf("Step %d: Expected %d notifications Result: %d", index, step.notifications, len(notifier.notifications))
+		}
+	}
+	if n := notifier.notifications[0]; n.UserID != "user" || n.ChannelID != "channel" {
+		t.Errorf("Expected a ping in the tracking channel. Result: %+v", n)
+	}
+
+	if found, _ := tracker.Untrack("test", "user", URL); !found || len(tracker.Watchlist("test", "user")) != 0 {
+		t.Error("Expected the product to be untracked")
+	}
+}
+
+type testTrackingSession struct {
+	testNotifier
+}
+
+func (s *testTrackingSession) OnMessage(chatapp.OnMessageCallback) error { return nil }
+func (s *testTrackingSession) OnProductProblemReport(chatapp.OnProductProblemReportCallback) error {
+	return nil
+}
+func (s *testTrackingSession) OnCommand(chatapp.OnCommandCallback) error           { return nil }
+func (s *testTrackingSession) OnTrackRequest(chatapp.OnTrackRequestCallback) error { return nil }
+
+type textActions struct {
+	testActions
+	texts []string
+}
+
+func (a *textActions) RespondWithText(text string) (string, error) {
+	a.texts = append(a.texts, text)
+	return "", nil
+}
+
+func TestTrackerCommands(t *testing.T) {
+	URL, _ := url.Parse("https://www.amazon.de/dp/B07MPCSHQD")
+	fetcher := &testFetcher{product: &chatapp.Product{Title: "Tastatur", Price: 25, Currency: "EUR", URL: URL}}
+	registry := scrapers.NewRegistry(amazonscraper.Scraper{})
+	ab := &AmazingBot{
+		Fetcher:  fetcher,
+		Scrapers: registry,
+		Tracker:  &tracker{Fetcher: fetcher, Scrapers: registry, MaxPerUser: 5, ErrorHandler: func(e error) { t.Error(e) }},
+	}
+	session := &testTrackingSession{}
+	run := func(name string, args ...string) string {
+		actions := &textActions{}
+		command := &chatapp.Command{Name: name, Args: args, Message: &chatapp.Message{AuthorID: "user", ChannelID: "channel", Actions: actions}}
+		switch name {
+		case "track":
+			ab.track(session, command)
+		case "untrack":
+			ab.untrack(session, command)
+		case "watchlist":
+			ab.watchlist(session, command)
+		}
+		if len(actions.texts) != 1 {
+			t.Fatalf("%s %v: Expected one response, got %v", name, args, actions.texts)
+		}
+		return actions.texts[0]
+	}
+
+	if text := run("watchlist"); !strings.Contains(text, "not tracking anything") {
+		t.Errorf("Expected an empty watchlist, got %q", text)
+	}
+	if text := run("track", URL.String(), "19,99", "dm"); !strings.Contains(text, "Tastatur") {
+		t.Errorf("Expected the product to be tracked, got %q", text)
+	}
+	watchlist := ab.Tracker.Watchlist("test", "user")
+	if len(watchlist) != 1 || watchlist[0].TargetPrice != 19.99 {
+		t.Fatalf("Expected a target price of 19.99 read the amazon.de way, got %+v", watchlist)
+	}
+	if text := run("watchlist"); !strings.Contains(text, "1. Tastatur") {
+		t.Errorf("Expected the product in the watchlist, got %q", text)
+	}
+	if text := run("track", URL.String(), "cheap"); !strings.HasPrefix(text, "Usage") {
+		t.Errorf("Expected usage for an invalid target price, got %q", text)
+	}
+
+	targets := []struct {
+		price       float32 //Of the product, 25 unless set
+		target      string
+		tracked     float32 //0 when refused
+		textPattern string
+	}{
+		{target: "19.99", tracked: 19.99, textPattern: "below €19.99"}, //Not 1999, the amazon.de way
+		{price: -1, target: "19.99", tracked: 19.99, textPattern: "below €19.99"},
+		{target: "30", tracked: 30, textPattern: "already below €30.00, at €25.00"},
+		{target: "99", textPattern: "far above the current price"},
+		{target: "1,500", tracked: 1.5, textPattern: "below €1.50"}, //1500 is too far above the price
+		{price: -1, target: "1,500", textPattern: "Is 1,500 €1.50 or €1500.00?"},
+	}
+	for _, test := range targets {
+		run("untrack", URL.String())
+		fetcher.product.Price = 25
+		if test.price < 0 {
+			fetcher.product.Price = 0
+		}
+		if text := run("track", URL.String(), test.target); !strings.Contains(text, test.textPattern) {
+			t.Errorf("%s: Expected %q in the response, got %q", test.target, test.textPattern, text)
+		}
+		watchlist := ab.Tracker.Watchlist("test", "user")
+		if test.tracked == 0 && len(watchlist) != 0 {
+			t.Errorf("%s: Expected the target price to be refused, got %+v", test.target, watchlist)
+		} else if test.tracked > 0 && (len(watchlist) != 1 || watchlist[0].TargetPrice != test.tracked) {
+			t.Errorf("%s: Expected a target price of %v, got %+v", test.target, test.tracked, watchlist)
+		}
+	}
+	fetcher.product.Price = 25
+	run("track", URL.String(), "19,99", "dm")
+
+	if text := run("untrack", "2"); !strings.Contains(text, "no product #2") {
+		t.Errorf("Expected an error for a number outside the watchlist, got %q", text)
+	}
+	if text := run("untrack", "1"); text != "Stopped tracking it" {
+		t.Errorf("Expected untracking by number, got %q", text)
+	}
+	run("track", URL.String())
+	if text := run("untrack", URL.String()); text != "Stopped tracking it" {
+		t.Errorf("Expected untracking by link, got %q", text)
+	}
+	if text := run("untrack", URL.String()); text != "You weren't tracking that product" {
+		t.Errorf("Expected nothing left to untrack, got %q", text)
+	}
+}